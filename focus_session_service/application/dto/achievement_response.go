@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"focusspot/focussessionservice/domain/entity"
+	"time"
+)
+
+// AchievementResponse is a badge with the user's progress towards it
+type AchievementResponse struct {
+	Key         string     `json:"key"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Metric      string     `json:"metric"`
+	Target      int        `json:"target"`
+	Current     int        `json:"current"`
+	Percent     float64    `json:"percent"` // capped at 100
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlockedAt,omitempty"`
+	SessionID   string     `json:"sessionId,omitempty"`
+}
+
+type AchievementsListResponse struct {
+	Achievements []AchievementResponse `json:"achievements"`
+	Unlocked     int                   `json:"unlocked"`
+	Total        int                   `json:"total"`
+}
+
+// ToAchievementResponse converts a rule, the user's progress and the unlocked
+// achievement, if any, to an AchievementResponse DTO
+func ToAchievementResponse(rule entity.AchievementRule, current int, achievement *entity.Achievement) AchievementResponse {
+	response := AchievementResponse{
+		Key:         rule.Key,
+		Name:        rule.Name,
+		Description: rule.Description,
+		Metric:      string(rule.Metric),
+		Target:      rule.Target,
+		Current:     current,
+		Percent:     100,
+	}
+
+	if current < rule.Target {
+		response.Percent = float64(current) / float64(rule.Target) * 100
+	}
+
+	if achievement != nil {
+		unlockedAt := achievement.UnlockedAt
+		response.Unlocked = true
+		response.UnlockedAt = &unlockedAt
+		response.Percent = 100
+		if achievement.SessionID != nil {
+			response.SessionID = achievement.SessionID.Hex()
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,7 @@
+package dto
+
+type CalendarExportRequest struct {
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone string `query:"timezone"` // IANA name; defaults to the user's preference
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type CalendarSubscriptionResponse struct {
+	Token     string    `json:"token"`
+	Path      string    `json:"path"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
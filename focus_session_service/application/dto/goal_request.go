@@ -0,0 +1,26 @@
+package dto
+
+type CreateGoalRequest struct {
+	Title        string  `json:"title" validate:"required"`
+	Metric       string  `json:"metric" validate:"required,oneof=focus_minutes completed_sessions average_focus"`
+	Period       string  `json:"period" validate:"required,oneof=daily weekly monthly"`
+	Target       float64 `json:"target" validate:"required,gt=0"` // average_focus targets are 1-10
+	Tag          string  `json:"tag,omitempty"`
+	LocationType string  `json:"locationType,omitempty"`
+}
+
+// UpdateGoalRequest changes the given fields. An empty tag or location type
+// removes that scope.
+type UpdateGoalRequest struct {
+	Title        string   `json:"title,omitempty"`
+	Metric       string   `json:"metric,omitempty" validate:"omitempty,oneof=focus_minutes completed_sessions average_focus"`
+	Period       string   `json:"period,omitempty" validate:"omitempty,oneof=daily weekly monthly"`
+	Target       *float64 `json:"target,omitempty" validate:"omitempty,gt=0"`
+	Tag          *string  `json:"tag,omitempty"`
+	LocationType *string  `json:"locationType,omitempty"`
+}
+
+type GetGoalProgressRequest struct {
+	Date     string `query:"date" validate:"omitempty,datetime=2006-01-02"` // any day in the period, default today
+	Timezone string `query:"timezone"`                                      // IANA name periods are in; defaults to the user's preference
+}
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"focusspot/focussessionservice/domain/entity"
+	"time"
+)
+
+type GoalResponse struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	Title        string    `json:"title"`
+	Metric       string    `json:"metric"`
+	Period       string    `json:"period"`
+	Target       float64   `json:"target"`
+	Tag          string    `json:"tag,omitempty"`
+	LocationType string    `json:"locationType,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// GoalProgressResponse is a goal's progress in one period. Percent may
+// exceed 100 once the target is passed.
+type GoalProgressResponse struct {
+	Goal                GoalResponse `json:"goal"`
+	PeriodStart         time.Time    `json:"periodStart"`
+	PeriodEnd           time.Time    `json:"periodEnd"`
+	Current             float64      `json:"current"`
+	Target              float64      `json:"target"`
+	Percent             float64      `json:"percent"`
+	Sessions            int          `json:"sessions"`
+	Achieved            bool         `json:"achieved"`
+	Projected           float64      `json:"projected"`
+	ProjectedPercent    float64      `json:"projectedPercent"`
+	ProjectedCompletion *time.Time   `json:"projectedCompletion,omitempty"`
+	OnTrack             bool         `json:"onTrack"`
+}
+
+// ToGoalResponse converts a Goal entity to a GoalResponse DTO
+func ToGoalResponse(goal *entity.Goal) GoalResponse {
+	return GoalResponse{
+		ID:           goal.ID.Hex(),
+		UserID:       goal.UserID.Hex(),
+		Title:        goal.Title,
+		Metric:       string(goal.Metric),
+		Period:       string(goal.Period),
+		Target:       goal.Target,
+		Tag:          goal.Tag,
+		LocationType: goal.LocationType,
+		CreatedAt:    goal.CreatedAt,
+		UpdatedAt:    goal.UpdatedAt,
+	}
+}
+
+// ToGoalProgressResponse converts a goal and its progress to a response DTO
+func ToGoalProgressResponse(goal *entity.Goal, progress entity.GoalProgress) GoalProgressResponse {
+	response := GoalProgressResponse{
+		Goal:                ToGoalResponse(goal),
+		PeriodStart:         progress.PeriodStart,
+		PeriodEnd:           progress.PeriodEnd,
+		Current:             progress.Current,
+		Target:              goal.Target,
+		Sessions:            progress.Sessions,
+		Achieved:            progress.Achieved,
+		Projected:           progress.Projected,
+		ProjectedCompletion: progress.ProjectedCompletion,
+		OnTrack:             progress.Achieved || progress.ProjectedCompletion != nil,
+	}
+
+	if goal.Target > 0 {
+		response.Percent = progress.Current / goal.Target * 100
+		response.ProjectedPercent = progress.Projected / goal.Target * 100
+	}
+
+	return response
+}
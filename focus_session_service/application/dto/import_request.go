@@ -0,0 +1,26 @@
+package dto
+
+// ImportCSVRequest describes an uploaded time tracker export
+type ImportCSVRequest struct {
+	Format   string             `json:"format"`             // toggl, clockify or generic
+	Timezone string             `json:"timezone,omitempty"` // IANA zone of times without an offset, defaults to the user's preference
+	Mapping  *CSVMappingRequest `json:"mapping,omitempty"`  // required for the generic format
+	DryRun   bool               `json:"dryRun"`
+}
+
+// CSVMappingRequest names the columns of a generic CSV file
+type CSVMappingRequest struct {
+	ID           string `json:"id,omitempty"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Notes        string `json:"notes,omitempty"`
+	Tags         string `json:"tags,omitempty"`
+	Location     string `json:"location,omitempty"`
+	Start        string `json:"start"`
+	StartTime    string `json:"startTime,omitempty"`
+	End          string `json:"end,omitempty"`
+	EndTime      string `json:"endTime,omitempty"`
+	Duration     string `json:"duration,omitempty"`
+	Layout       string `json:"layout,omitempty"`
+	TagSeparator string `json:"tagSeparator,omitempty"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+// Import result statuses
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+	ImportReady     = "ready" // would be created; dry runs only
+)
+
+// ImportReport summarizes an import with one result per event or row. A dry
+// run reports what would be imported without saving anything.
+type ImportReport struct {
+	DryRun     bool                   `json:"dryRun"`
+	Ready      int                    `json:"ready,omitempty"`
+	Created    int                    `json:"created"`
+	Duplicates int                    `json:"duplicates"`
+	Invalid    int                    `json:"invalid"`
+	Results    []ImportResultResponse `json:"results"`
+}
+
+type ImportResultResponse struct {
+	Index     int                   `json:"index"` // 1-based position in the file
+	UID       string                `json:"uid,omitempty"`
+	Title     string                `json:"title,omitempty"`
+	Status    string                `json:"status"`
+	SessionID string                `json:"sessionId,omitempty"`
+	Error     string                `json:"error,omitempty"`
+	Session   *FocusSessionResponse `json:"session,omitempty"` // preview of the session, dry runs only
+}
+
+// Add records a result and updates the totals
+func (r *ImportReport) Add(result ImportResultResponse) {
+	switch result.Status {
+	case ImportReady:
+		r.Ready++
+	case ImportCreated:
+		r.Created++
+	case ImportDuplicate:
+		r.Duplicates++
+	case ImportInvalid:
+		r.Invalid++
+	}
+	r.Results = append(r.Results, result)
+}
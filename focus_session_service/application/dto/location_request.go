@@ -0,0 +1,69 @@
+package dto
+
+type CreateLocationRequest struct {
+	Name         string                `json:"name" validate:"required"`
+	Address      string                `json:"address,omitempty"`
+	Latitude     *float64              `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude    *float64              `json:"longitude" validate:"required,min=-180,max=180"`
+	Type         string                `json:"type" validate:"required,oneof=cafe library coworking office home park other"`
+	Amenities    []string              `json:"amenities,omitempty"`
+	OpeningHours []OpeningHoursRequest `json:"openingHours,omitempty"`
+	Timezone     string                `json:"timezone,omitempty"` // IANA name opening hours are in
+	Private      bool                  `json:"private,omitempty"`  // only the creator sees it; home spots always are
+}
+
+// OpeningHoursRequest is one day's opening hours. A closing time at or before
+// the opening time is on the next day.
+type OpeningHoursRequest struct {
+	Day   string `json:"day" validate:"required"`   // weekday name, e.g. "monday"
+	Open  string `json:"open" validate:"required"`  // HH:MM
+	Close string `json:"close" validate:"required"` // HH:MM
+}
+
+// UpdateLocationRequest changes the given fields. Amenities and opening
+// hours are replaced as a whole; an empty list removes them all.
+type UpdateLocationRequest struct {
+	Name         string                 `json:"name,omitempty"`
+	Address      *string                `json:"address,omitempty"`
+	Latitude     *float64               `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude    *float64               `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	Type         string                 `json:"type,omitempty" validate:"omitempty,oneof=cafe library coworking office home park other"`
+	Amenities    *[]string              `json:"amenities,omitempty"`
+	OpeningHours *[]OpeningHoursRequest `json:"openingHours,omitempty"`
+	Timezone     *string                `json:"timezone,omitempty"`
+	Private      *bool                  `json:"private,omitempty"` // home spots stay private
+}
+
+type GetLocationsRequest struct {
+	Type      string   `query:"type" validate:"omitempty,oneof=cafe library coworking office home park other"`
+	Query     string   `query:"q"`         // part of the name
+	Amenities []string `query:"amenities"` // comma separated; spots must have all
+	Mine      bool     `query:"mine"`      // only spots the user added
+	Limit     int      `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int      `query:"offset" validate:"omitempty,min=0"`
+}
+
+// GetNearbyLocationsRequest finds spots within Radius meters (default 2 km)
+// of a point
+type GetNearbyLocationsRequest struct {
+	Latitude  *float64 `query:"lat" validate:"required,min=-90,max=90"`
+	Longitude *float64 `query:"lng" validate:"required,min=-180,max=180"`
+	Radius    float64  `query:"radius" validate:"omitempty,gt=0,max=50000"`
+	Type      string   `query:"type" validate:"omitempty,oneof=cafe library coworking office home park other"`
+	Limit     int      `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// GetRecommendationsRequest asks where and when to hold a session. At is the
+// planned start in RFC 3339; without it spots are ranked for any time. Spots
+// near Latitude and Longitude are considered along with those the user has
+// been to, and Community blends in other users' scores for untried spots.
+type GetRecommendationsRequest struct {
+	At        string   `query:"at"`
+	Latitude  *float64 `query:"lat" validate:"omitempty,min=-90,max=90"`
+	Longitude *float64 `query:"lng" validate:"omitempty,min=-180,max=180"`
+	Radius    float64  `query:"radius" validate:"omitempty,gt=0,max=50000"`
+	Type      string   `query:"type" validate:"omitempty,oneof=cafe library coworking office home park other"`
+	Limit     int      `query:"limit" validate:"omitempty,min=1,max=50"`
+	Community bool     `query:"community"`
+	Timezone  string   `query:"timezone"`
+}
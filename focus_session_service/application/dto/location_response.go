@@ -0,0 +1,156 @@
+package dto
+
+import (
+	"focusspot/focussessionservice/domain/entity"
+	"strings"
+	"time"
+)
+
+type LocationResponse struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Address      string                 `json:"address,omitempty"`
+	Latitude     float64                `json:"latitude"`
+	Longitude    float64                `json:"longitude"`
+	Type         string                 `json:"type"`
+	Amenities    []string               `json:"amenities"`
+	OpeningHours []OpeningHoursResponse `json:"openingHours"`
+	Timezone     string                 `json:"timezone,omitempty"`
+	Private      bool                   `json:"private"`
+	CreatedBy    string                 `json:"createdBy"`
+	CreatedAt    time.Time              `json:"createdAt"`
+	UpdatedAt    time.Time              `json:"updatedAt"`
+}
+
+type OpeningHoursResponse struct {
+	Day   string `json:"day"` // weekday name, e.g. "monday"
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// NearbyLocationsResponse lists spots near a point, nearest first
+type NearbyLocationsResponse struct {
+	ScoreVersion string                   `json:"scoreVersion"` // the formula of productivity
+	Locations    []NearbyLocationResponse `json:"locations"`
+}
+
+// NearbyLocationResponse is a spot, how far away it is, and how the user
+// focuses there. Productivity is missing for spots the user has no completed
+// sessions at.
+type NearbyLocationResponse struct {
+	LocationResponse
+	Distance     float64                       `json:"distance"` // meters
+	Productivity *LocationProductivityResponse `json:"productivity,omitempty"`
+}
+
+type LocationProductivityResponse struct {
+	Sessions            int       `json:"sessions"` // completed sessions
+	Minutes             int       `json:"minutes"`
+	AverageProductivity float64   `json:"averageProductivity"`
+	LastVisit           time.Time `json:"lastVisit"`
+}
+
+// ToLocationResponse converts a Location entity to a LocationResponse DTO
+func ToLocationResponse(location *entity.Location) LocationResponse {
+	response := LocationResponse{
+		ID:           location.ID.Hex(),
+		Name:         location.Name,
+		Address:      location.Address,
+		Latitude:     location.Coordinates.Latitude(),
+		Longitude:    location.Coordinates.Longitude(),
+		Type:         string(location.Type),
+		Amenities:    location.Amenities,
+		OpeningHours: make([]OpeningHoursResponse, 0, len(location.OpeningHours)),
+		Timezone:     location.Timezone,
+		Private:      location.Private,
+		CreatedBy:    location.CreatedBy.Hex(),
+		CreatedAt:    location.CreatedAt,
+		UpdatedAt:    location.UpdatedAt,
+	}
+
+	if response.Amenities == nil {
+		response.Amenities = []string{}
+	}
+
+	for _, hours := range location.OpeningHours {
+		response.OpeningHours = append(response.OpeningHours, OpeningHoursResponse{
+			Day:   strings.ToLower(hours.Day.String()),
+			Open:  hours.Open,
+			Close: hours.Close,
+		})
+	}
+
+	return response
+}
+
+// ToNearbyLocationResponse converts a nearby spot and the user's productivity
+// there, if any, to a response DTO
+func ToNearbyLocationResponse(location *entity.NearbyLocation, productivity *entity.LocationProductivity) NearbyLocationResponse {
+	response := NearbyLocationResponse{
+		LocationResponse: ToLocationResponse(&location.Location),
+		Distance:         location.Distance,
+	}
+
+	if productivity != nil {
+		response.Productivity = &LocationProductivityResponse{
+			Sessions:            productivity.Sessions,
+			Minutes:             productivity.Minutes,
+			AverageProductivity: productivity.AverageProductivity,
+			LastVisit:           productivity.LastVisit,
+		}
+	}
+
+	return response
+}
+
+// RecommendationsResponse lists the best spots for a session, and the best
+// times of the week to focus, best first
+type RecommendationsResponse struct {
+	ScoreVersion string                       `json:"scoreVersion"` // the formula of productivity
+	Timezone     string                       `json:"timezone"`
+	PlannedAt    *time.Time                   `json:"plannedAt,omitempty"`
+	Spots        []SpotRecommendationResponse `json:"spots"`
+	Times        []TimeRecommendationResponse `json:"times"`
+}
+
+type SpotRecommendationResponse struct {
+	Location    LocationResponse `json:"location"`
+	Score       float64          `json:"score"`    // expected productivity
+	Sessions    int              `json:"sessions"` // sessions the score rests on
+	Source      string           `json:"source"`   // history, community or blended
+	Explanation string           `json:"explanation"`
+}
+
+type TimeRecommendationResponse struct {
+	Weekday      string  `json:"weekday"`
+	TimeOfDay    string  `json:"timeOfDay"`
+	LocationType string  `json:"locationType,omitempty"` // missing for any spot
+	Score        float64 `json:"score"`
+	Sessions     int     `json:"sessions"`
+	Explanation  string  `json:"explanation"`
+}
+
+// ToSpotRecommendationResponse converts a spot recommendation to a response
+// DTO
+func ToSpotRecommendationResponse(recommendation entity.SpotRecommendation) SpotRecommendationResponse {
+	return SpotRecommendationResponse{
+		Location:    ToLocationResponse(recommendation.Location),
+		Score:       recommendation.Score,
+		Sessions:    recommendation.Sessions,
+		Source:      string(recommendation.Source),
+		Explanation: recommendation.Explanation,
+	}
+}
+
+// ToTimeRecommendationResponse converts a time recommendation to a response
+// DTO
+func ToTimeRecommendationResponse(recommendation entity.TimeRecommendation) TimeRecommendationResponse {
+	return TimeRecommendationResponse{
+		Weekday:      strings.ToLower(recommendation.Weekday.String()),
+		TimeOfDay:    string(recommendation.TimeOfDay),
+		LocationType: string(recommendation.LocationType),
+		Score:        recommendation.Score,
+		Sessions:     recommendation.Sessions,
+		Explanation:  recommendation.Explanation,
+	}
+}
@@ -0,0 +1,8 @@
+package dto
+
+// UpdatePreferencesRequest changes the given preferences. An empty value
+// removes the preference, so that the default is used.
+type UpdatePreferencesRequest struct {
+	Timezone *string `json:"timezone,omitempty"` // IANA name, e.g. "Asia/Ho_Chi_Minh"
+	Scorer   *string `json:"scorer,omitempty"`   // classic, weighted or duration_normalized
+}
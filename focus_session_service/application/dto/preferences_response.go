@@ -0,0 +1,36 @@
+package dto
+
+import "focusspot/focussessionservice/domain/entity"
+
+type PreferencesResponse struct {
+	Timezone     string   `json:"timezone"` // the timezone used when a request names none
+	Scorer       string   `json:"scorer"`
+	ScoreVersion string   `json:"scoreVersion"`
+	Scorers      []string `json:"scorers"` // the scorers to choose from
+}
+
+// ToPreferencesResponse converts UserPreferences to a PreferencesResponse
+// DTO. Users without preferences get the defaults.
+func ToPreferencesResponse(preferences *entity.UserPreferences) PreferencesResponse {
+	response := PreferencesResponse{
+		Timezone:     "UTC",
+		Scorer:       entity.DefaultScorer.Name(),
+		ScoreVersion: entity.DefaultScorer.Version(),
+		Scorers:      entity.ScorerNames(),
+	}
+
+	if preferences == nil {
+		return response
+	}
+
+	if preferences.Timezone != "" {
+		response.Timezone = preferences.Timezone
+	}
+
+	if scorer, ok := entity.ScorerByName(preferences.Scorer); ok {
+		response.Scorer = scorer.Name()
+		response.ScoreVersion = scorer.Version()
+	}
+
+	return response
+}
@@ -13,8 +13,21 @@ type CreateSessionRequest struct {
 	LocationID      string                  `json:"locationId,omitempty"`
 	LocationDetails *LocationDetailsRequest `json:"locationDetails,omitempty"`
 	Tags            []string                `json:"tags,omitempty"`
+	Mode            string                  `json:"mode,omitempty" validate:"omitempty,oneof=single pomodoro"`
+	Pomodoro        *PomodoroConfigRequest  `json:"pomodoro,omitempty"`
 }
 
+// PomodoroConfigRequest configures a pomodoro session. Zero values use the defaults (25/5/15 minutes, 4 cycles)
+type PomodoroConfigRequest struct {
+	WorkDuration       int `json:"workDuration,omitempty" validate:"omitempty,min=1"`
+	ShortBreakDuration int `json:"shortBreakDuration,omitempty" validate:"omitempty,min=1"`
+	LongBreakDuration  int `json:"longBreakDuration,omitempty" validate:"omitempty,min=1"`
+	Cycles             int `json:"cycles,omitempty" validate:"omitempty,min=1"`
+}
+
+// LocationDetailsRequest describes a place that is not a saved spot. Given
+// a location ID, sessions and series copy the spot's details instead, and
+// details given without one detach them from their spot.
 type LocationDetailsRequest struct {
 	Name      string  `json:"name" validate:"required"`
 	Address   string  `json:"address"`
@@ -44,18 +57,64 @@ type EndSessionRequest struct {
 }
 
 type GetSessionsRequest struct {
-	StartDate string `query:"startDate" validate:"omitempty,datetime=2006-01-02"`
-	EndDate   string `query:"endDate" validate:"omitempty,datetime=2006-01-02"`
-	Limit     int    `query:"limit,default=20" validate:"omitempty,min=1,max=100"`
-	Offset    int    `query:"offset,default=0" validate:"omitempty,min=0"`
+	StartDate    string   `query:"startDate" validate:"omitempty,datetime=2006-01-02"`
+	EndDate      string   `query:"endDate" validate:"omitempty,datetime=2006-01-02"`
+	Status       []string `query:"status"` // comma separated
+	Tags         []string `query:"tags"`   // comma separated
+	TagMatch     string   `query:"tagMatch" validate:"omitempty,oneof=any all"`
+	LocationID   string   `query:"locationId"`
+	LocationType string   `query:"locationType"`
+	MinRating    *int     `query:"minRating" validate:"omitempty,min=1,max=5"`
+	MaxRating    *int     `query:"maxRating" validate:"omitempty,min=1,max=5"`
+	MinFocus     *int     `query:"minFocus" validate:"omitempty,min=1,max=10"`
+	MaxFocus     *int     `query:"maxFocus" validate:"omitempty,min=1,max=10"`
+	MinDuration  *int     `query:"minDuration" validate:"omitempty,min=0"` // minutes
+	MaxDuration  *int     `query:"maxDuration" validate:"omitempty,min=0"`
+	Sort         string   `query:"sort"`
+	Order        string   `query:"order" validate:"omitempty,oneof=asc desc"`
+	Cursor       string   `query:"cursor"`   // from nextCursor or prevCursor; replaces offset
+	Timezone     string   `query:"timezone"` // IANA name dates are read in; defaults to the user's preference
+	Limit        int      `query:"limit,default=20" validate:"omitempty,min=1,max=100"`
+	Offset       int      `query:"offset,default=0" validate:"omitempty,min=0"`
+}
+
+// SearchSessionsRequest is a text search narrowed by the session list
+// filters. Results are ranked by relevance, so the sort and cursor are not
+// used.
+type SearchSessionsRequest struct {
+	Query string `query:"q" validate:"required"`
+	GetSessionsRequest
 }
 
 type GetProductivityStatsRequest struct {
 	StartDate string `query:"startDate" validate:"omitempty,datetime=2006-01-02"`
 	EndDate   string `query:"endDate" validate:"omitempty,datetime=2006-01-02"`
+	Timezone  string `query:"timezone"` // IANA name; defaults to the user's preference
 }
 
+// GetProductivityTrendsRequest selects the window of trends. Without From,
+// the window is the Limit periods up to To (by default 30 days, 12 weeks, 12
+// months, 8 quarters or 5 years); with it, Limit caps the number of periods.
+// To defaults to now.
 type GetProductivityTrendsRequest struct {
-	Period entity.Period `query:"period,default=weekly" validate:"omitempty,oneof=daily weekly monthly"`
-	Limit  int           `query:"limit,default=12" validate:"omitempty,min=1,max=52"`
+	Period   entity.Period `query:"period,default=weekly" validate:"omitempty,oneof=daily weekly monthly quarterly yearly"`
+	From     string        `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string        `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Limit    int           `query:"limit" validate:"omitempty,min=1,max=366"`
+	Compare  bool          `query:"compare"`  // also summarize the window just before
+	Timezone string        `query:"timezone"` // IANA name; defaults to the user's preference
+}
+
+// GetProductivityHeatmapRequest selects the year of the heatmap, by default
+// the current one
+type GetProductivityHeatmapRequest struct {
+	Year     int    `query:"year" validate:"omitempty,min=1970,max=9999"`
+	Timezone string `query:"timezone"` // IANA name; defaults to the user's preference
+}
+
+type ExportSessionsRequest struct {
+	Format    string `query:"format,default=csv" validate:"omitempty,oneof=csv json ndjson"`
+	StartDate string `query:"startDate" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `query:"endDate" validate:"omitempty,datetime=2006-01-02"`
+	Timezone  string `query:"timezone"` // IANA name; defaults to the user's preference
 }
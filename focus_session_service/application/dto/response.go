@@ -15,6 +15,13 @@ type FocusSessionResponse struct {
 	Duration          int                      `json:"duration"`
 	ActualDuration    *int                     `json:"actualDuration,omitempty"`
 	Status            string                   `json:"status"`
+	Mode              string                   `json:"mode"`
+	Pomodoro          *PomodoroResponse        `json:"pomodoro,omitempty"`
+	Pauses            []PauseIntervalResponse  `json:"pauses,omitempty"`
+	SeriesID          string                   `json:"seriesId,omitempty"`
+	OccurrenceTime    *time.Time               `json:"occurrenceTime,omitempty"`
+	ExternalUID       string                   `json:"externalUid,omitempty"`
+	PausedDuration    int                      `json:"pausedDuration,omitempty"` // in minutes
 	LocationID        string                   `json:"locationId,omitempty"`
 	LocationDetails   *LocationDetailsResponse `json:"locationDetails,omitempty"`
 	Tags              []string                 `json:"tags,omitempty"`
@@ -25,10 +32,27 @@ type FocusSessionResponse struct {
 	Mood              *int                     `json:"mood,omitempty"`
 	Distractions      *int                     `json:"distractions,omitempty"`
 	ProductivityScore *float64                 `json:"productivityScore,omitempty"`
+	ScoreVersion      string                   `json:"scoreVersion,omitempty"` // the formula of productivityScore
 	CreatedAt         time.Time                `json:"createdAt"`
 	UpdatedAt         time.Time                `json:"updatedAt"`
 }
 
+type PomodoroResponse struct {
+	WorkDuration       int    `json:"workDuration"`
+	ShortBreakDuration int    `json:"shortBreakDuration"`
+	LongBreakDuration  int    `json:"longBreakDuration"`
+	Cycles             int    `json:"cycles"`
+	Phase              string `json:"phase"`
+	CycleIndex         int    `json:"cycleIndex"`
+	CompletedPomodoros int    `json:"completedPomodoros"`
+	PhaseRemaining     *int   `json:"phaseRemaining,omitempty"` // in seconds
+}
+
+type PauseIntervalResponse struct {
+	PausedAt  time.Time  `json:"pausedAt"`
+	ResumedAt *time.Time `json:"resumedAt,omitempty"`
+}
+
 type LocationDetailsResponse struct {
 	Name      string  `json:"name"`
 	Address   string  `json:"address,omitempty"`
@@ -38,15 +62,18 @@ type LocationDetailsResponse struct {
 }
 
 type SessionsListResponse struct {
-	Sessions []FocusSessionResponse `json:"sessions"`
-	Total    int                    `json:"total"`
-	Limit    int                    `json:"limit"`
-	Offset   int                    `json:"offset"`
+	Sessions   []FocusSessionResponse `json:"sessions"`
+	Total      int                    `json:"total"`
+	Limit      int                    `json:"limit"`
+	Offset     int                    `json:"offset"`
+	NextCursor string                 `json:"nextCursor,omitempty"`
+	PrevCursor string                 `json:"prevCursor,omitempty"`
 }
 
 type DateRange struct {
 	StartDate time.Time `json:"startDate"`
 	EndDate   time.Time `json:"endDate"`
+	Timezone  string    `json:"timezone"` // IANA name days and times of day are read in
 }
 
 // ProductivityStatsResponse contains detailed productivity analytics for a user
@@ -64,6 +91,14 @@ type ProductivityStatsResponse struct {
 	AverageMood         float64 `json:"averageMood"`
 	AverageDistractions float64 `json:"averageDistractions"`
 
+	// Pomodoro work phases finished across completed sessions
+	PomodorosCompleted int `json:"pomodorosCompleted"`
+
+	// Average productivity score, and the score combining it with focus,
+	// distractions and the completion rate (0-10)
+	AverageProductivity float64 `json:"averageProductivity"`
+	OverallScore        float64 `json:"overallScore"`
+
 	// Productivity by day of week
 	ProductivityByDay map[string]float64 `json:"productivityByDay"`
 	MostProductiveDay string             `json:"mostProductiveDay"`
@@ -80,24 +115,89 @@ type ProductivityStatsResponse struct {
 	ProductivityByLocationType map[string]float64 `json:"productivityByLocationType"`
 	MostProductiveLocationType string             `json:"mostProductiveLocationType"`
 
+	// Productivity by tag
+	ProductivityByTag map[string]float64 `json:"productivityByTag"`
+	MostProductiveTag string             `json:"mostProductiveTag"`
+
+	// The formula the productivity figures were scored with
+	ScoreVersion string `json:"scoreVersion"`
+
 	// Date range for the stats
 	DateRange DateRange `json:"dateRange"`
 }
 
-// ProductivityTrendsResponse contains productivity data over time
+// ProductivityTrendsResponse contains productivity data over time, one value
+// per period of the window
 type ProductivityTrendsResponse struct {
-	Period       entity.Period `json:"period"` // daily, weekly, monthly
+	Period       entity.Period `json:"period"` // daily, weekly, monthly, quarterly, yearly
+	Timezone     string        `json:"timezone"`
+	ScoreVersion string        `json:"scoreVersion"` // the formula of productivity
+	DateRange    DateRange     `json:"dateRange"`
+	Starts       []time.Time   `json:"starts"` // start of each period
 	Dates        []string      `json:"dates"`
+	Sessions     []int         `json:"sessions"`  // completed sessions
 	Durations    []int         `json:"durations"` // in minutes
 	Ratings      []float64     `json:"ratings"`
 	Focus        []float64     `json:"focus"`
 	Energy       []float64     `json:"energy"`
 	Mood         []float64     `json:"mood"`
 	Productivity []float64     `json:"productivity"` // overall productivity score
+
+	// Average of the periods with a productivity score, and the least-squares
+	// trend through them. Improving means the slope is positive with a p-value
+	// below 0.05.
+	AverageProductivity float64 `json:"averageProductivity"`
+	TrendSlope          float64 `json:"trendSlope"` // productivity change per period
+	TrendPValue         float64 `json:"trendPValue"`
+	Improving           bool    `json:"improving"`
+
+	// Set when a comparison with the previous window is asked for
+	Comparison *TrendsComparison `json:"comparison,omitempty"`
+}
+
+// ProductivityHeatmapResponse contains a year of daily focus time and the
+// average productivity of every hour of the week
+type ProductivityHeatmapResponse struct {
+	Year          int                 `json:"year"`
+	Timezone      string              `json:"timezone"`
+	ScoreVersion  string              `json:"scoreVersion"` // the formula of productivity
+	DateRange     DateRange           `json:"dateRange"`
+	Days          []entity.HeatmapDay `json:"days"` // every day of the year
+	TotalMinutes  int                 `json:"totalMinutes"`
+	TotalSessions int                 `json:"totalSessions"` // completed sessions
+	ActiveDays    int                 `json:"activeDays"`    // days with focus time
+
+	// Least minutes of each intensity level from 1 up. Level 0 is a day
+	// without focus time.
+	LevelThresholds []int `json:"levelThresholds"`
+
+	// Indexed by weekday, Sunday first, then by hour of the day
+	HourOfWeekProductivity [7][24]float64 `json:"hourOfWeekProductivity"`
+	HourOfWeekSessions     [7][24]int     `json:"hourOfWeekSessions"`
+}
+
+// TrendsComparison sums up the window of the same number of periods just
+// before the trends, and how the trends changed from it
+type TrendsComparison struct {
+	DateRange           DateRange `json:"dateRange"`
+	Sessions            int       `json:"sessions"`
+	Duration            int       `json:"duration"` // in minutes
+	AverageProductivity float64   `json:"averageProductivity"`
+
+	// Current window minus the previous one
+	SessionsChange     int     `json:"sessionsChange"`
+	DurationChange     int     `json:"durationChange"`
+	ProductivityChange float64 `json:"productivityChange"`
 }
 
 // ToFocusSessionResponse converts a FocusSession entity to a FocusSessionResponse DTO
 func ToFocusSessionResponse(session *entity.FocusSession) FocusSessionResponse {
+	return ToScoredFocusSessionResponse(session, entity.DefaultScorer)
+}
+
+// ToScoredFocusSessionResponse converts a FocusSession entity to a
+// FocusSessionResponse DTO, scoring it with the given scorer
+func ToScoredFocusSessionResponse(session *entity.FocusSession, scorer entity.ProductivityScorer) FocusSessionResponse {
 	response := FocusSessionResponse{
 		ID:             session.ID.Hex(),
 		UserID:         session.UserID.Hex(),
@@ -108,6 +208,7 @@ func ToFocusSessionResponse(session *entity.FocusSession) FocusSessionResponse {
 		Duration:       session.Duration,
 		ActualDuration: session.ActualDuration,
 		Status:         string(session.Status),
+		ExternalUID:    session.ExternalUID,
 		Tags:           session.Tags,
 		Notes:          session.Notes,
 		Rating:         session.Rating,
@@ -120,8 +221,48 @@ func ToFocusSessionResponse(session *entity.FocusSession) FocusSessionResponse {
 	}
 
 	if session.Status == entity.StatusCompleted && session != nil {
-		score := session.CalculateProductivityScore()
+		score := scorer.Score(session)
 		response.ProductivityScore = &score
+		response.ScoreVersion = scorer.Version()
+	}
+
+	response.Mode = string(entity.ModeSingle)
+	if session.Mode != "" {
+		response.Mode = string(session.Mode)
+	}
+
+	if session.Pomodoro != nil {
+		response.Pomodoro = &PomodoroResponse{
+			WorkDuration:       session.Pomodoro.WorkDuration,
+			ShortBreakDuration: session.Pomodoro.ShortBreakDuration,
+			LongBreakDuration:  session.Pomodoro.LongBreakDuration,
+			Cycles:             session.Pomodoro.Cycles,
+			Phase:              string(session.Pomodoro.Phase),
+			CycleIndex:         session.Pomodoro.CycleIndex,
+			CompletedPomodoros: session.Pomodoro.CompletedPomodoros,
+		}
+	}
+
+	if len(session.Pauses) > 0 {
+		response.Pauses = make([]PauseIntervalResponse, 0, len(session.Pauses))
+		for _, pause := range session.Pauses {
+			response.Pauses = append(response.Pauses, PauseIntervalResponse{
+				PausedAt:  pause.PausedAt,
+				ResumedAt: pause.ResumedAt,
+			})
+		}
+
+		// Sessions that are still running count an open pause until now
+		until := time.Now()
+		if session.EndTime != nil {
+			until = *session.EndTime
+		}
+		response.PausedDuration = int(session.PausedDuration(until).Minutes())
+	}
+
+	if session.SeriesID != nil {
+		response.SeriesID = session.SeriesID.Hex()
+		response.OccurrenceTime = session.OccurrenceTime
 	}
 
 	if session.LocationID != nil {
@@ -192,6 +333,9 @@ func ToProductivityStatsResponse(stats *entity.ProductivityStats, dateRange Date
 		AverageEnergy:              stats.AverageEnergy,
 		AverageMood:                stats.AverageMood,
 		AverageDistractions:        stats.AverageDistractions,
+		PomodorosCompleted:         stats.PomodorosCompleted,
+		AverageProductivity:        stats.AverageProductivity,
+		OverallScore:               stats.GetOverallProductivityScore(),
 		
 		ProductivityByDay:          productivityByDay,
 		MostProductiveDay:          dayNames[stats.MostProductiveDay],
@@ -202,7 +346,71 @@ func ToProductivityStatsResponse(stats *entity.ProductivityStats, dateRange Date
 		MostProductiveLocation:     stats.MostProductiveLocation,
 		ProductivityByLocationType: stats.ProductivityByLocationType,
 		MostProductiveLocationType: stats.MostProductiveLocationType,
+		ProductivityByTag:          stats.ProductivityByTag,
+		MostProductiveTag:          stats.MostProductiveTag,
 		
 		DateRange:                  dateRange,
 	}
 }
+
+// ToProductivityTrendsResponse converts domain trends to response DTO
+func ToProductivityTrendsResponse(trends *entity.ProductivityTrends, dateRange DateRange) ProductivityTrendsResponse {
+	fit := trends.Fit()
+
+	return ProductivityTrendsResponse{
+		Period:              trends.Period,
+		Timezone:            dateRange.Timezone,
+		DateRange:           dateRange,
+		Starts:              trends.Starts,
+		Dates:               trends.Dates,
+		Sessions:            trends.Sessions,
+		Durations:           trends.Durations,
+		Ratings:             trends.Ratings,
+		Focus:               trends.Focus,
+		Energy:              trends.Energy,
+		Mood:                trends.Mood,
+		Productivity:        trends.Productivity,
+		AverageProductivity: trends.GetAverageProductivity(),
+		TrendSlope:          fit.Slope,
+		TrendPValue:         fit.PValue,
+		Improving:           trends.IsImproving(),
+	}
+}
+
+// ToTrendsComparison compares trends with those of the previous window
+func ToTrendsComparison(current, previous *entity.ProductivityTrends, dateRange DateRange) TrendsComparison {
+	comparison := TrendsComparison{
+		DateRange:           dateRange,
+		Sessions:            previous.TotalSessions(),
+		Duration:            previous.TotalDuration(),
+		AverageProductivity: previous.GetAverageProductivity(),
+	}
+
+	comparison.SessionsChange = current.TotalSessions() - comparison.Sessions
+	comparison.DurationChange = current.TotalDuration() - comparison.Duration
+	comparison.ProductivityChange = current.GetAverageProductivity() - comparison.AverageProductivity
+	return comparison
+}
+
+// ToProductivityHeatmapResponse converts a domain heatmap to response DTO
+func ToProductivityHeatmapResponse(heatmap *entity.ProductivityHeatmap, dateRange DateRange) ProductivityHeatmapResponse {
+	response := ProductivityHeatmapResponse{
+		Year:                   heatmap.Year,
+		Timezone:               dateRange.Timezone,
+		DateRange:              dateRange,
+		Days:                   heatmap.Days,
+		LevelThresholds:        heatmap.LevelThresholds,
+		HourOfWeekProductivity: heatmap.HourOfWeekProductivity,
+		HourOfWeekSessions:     heatmap.HourOfWeekSessions,
+	}
+
+	for _, day := range heatmap.Days {
+		response.TotalMinutes += day.Minutes
+		response.TotalSessions += day.Sessions
+		if day.Minutes > 0 {
+			response.ActiveDays++
+		}
+	}
+
+	return response
+}
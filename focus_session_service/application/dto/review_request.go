@@ -0,0 +1,31 @@
+package dto
+
+// ReviewLocationRequest rates a spot's aspects from 1 to 5, replacing the
+// user's earlier review of it. Aspects left out are unrated; a review needs
+// at least one rating or a text.
+type ReviewLocationRequest struct {
+	Noise    *int   `json:"noise,omitempty" validate:"omitempty,min=1,max=5"`    // 1 quiet to 5 loud
+	WiFi     *int   `json:"wifi,omitempty" validate:"omitempty,min=1,max=5"`     // 1 unusable to 5 fast and reliable
+	Outlets  *int   `json:"outlets,omitempty" validate:"omitempty,min=1,max=5"`  // 1 none to 5 at every seat
+	Seating  *int   `json:"seating,omitempty" validate:"omitempty,min=1,max=5"`  // 1 uncomfortable to 5 comfortable
+	Crowding *int   `json:"crowding,omitempty" validate:"omitempty,min=1,max=5"` // 1 empty to 5 packed
+	Text     string `json:"text,omitempty" validate:"omitempty,max=2000"`
+}
+
+type GetLocationReviewsRequest struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+}
+
+// GetSpotInsightsRequest lists the spots the user focuses best at, of Type
+// and with average ratings in the given ranges, e.g. noise "1-2" for quiet
+// spots. A single number matches that rating exactly.
+type GetSpotInsightsRequest struct {
+	Noise    string `query:"noise"`
+	WiFi     string `query:"wifi"`
+	Outlets  string `query:"outlets"`
+	Seating  string `query:"seating"`
+	Crowding string `query:"crowding"`
+	Type     string `query:"type" validate:"omitempty,oneof=cafe library coworking office home park other"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"focusspot/focussessionservice/domain/entity"
+	"time"
+)
+
+type LocationReviewResponse struct {
+	ID         string    `json:"id"`
+	LocationID string    `json:"locationId"`
+	UserID     string    `json:"userId"`
+	Noise      *int      `json:"noise,omitempty"`
+	WiFi       *int      `json:"wifi,omitempty"`
+	Outlets    *int      `json:"outlets,omitempty"`
+	Seating    *int      `json:"seating,omitempty"`
+	Crowding   *int      `json:"crowding,omitempty"`
+	Text       string    `json:"text,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// LocationRatingsResponse is a spot's average rating of each aspect. Aspects
+// no review rated are missing.
+type LocationRatingsResponse struct {
+	Reviews  int      `json:"reviews"`
+	Noise    *float64 `json:"noise,omitempty"`
+	WiFi     *float64 `json:"wifi,omitempty"`
+	Outlets  *float64 `json:"outlets,omitempty"`
+	Seating  *float64 `json:"seating,omitempty"`
+	Crowding *float64 `json:"crowding,omitempty"`
+}
+
+// LocationReviewsResponse is a spot's ratings over all its reviews, and a
+// page of the reviews, most recently updated first
+type LocationReviewsResponse struct {
+	Ratings LocationRatingsResponse  `json:"ratings"`
+	Reviews []LocationReviewResponse `json:"reviews"`
+}
+
+// SpotInsightsResponse is how each aspect of the spots goes with the user's
+// focus and distractions, over every rated spot they have been to, and the
+// matching spots they focus best at
+type SpotInsightsResponse struct {
+	Correlations []RatingCorrelationResponse `json:"correlations"`
+	Spots        []SpotFitResponse           `json:"spots"`
+}
+
+type RatingCorrelationResponse struct {
+	Aspect       string              `json:"aspect"`
+	Focus        CorrelationResponse `json:"focus"`
+	Distractions CorrelationResponse `json:"distractions"`
+}
+
+// CorrelationResponse is Pearson's r over the spots, missing when there are
+// too few spots or no spread to tell
+type CorrelationResponse struct {
+	Coefficient *float64 `json:"coefficient,omitempty"`
+	PValue      float64  `json:"pValue"`
+	Spots       int      `json:"spots"`
+	Sessions    int      `json:"sessions"`
+}
+
+type SpotFitResponse struct {
+	Location            LocationResponse        `json:"location"`
+	Ratings             LocationRatingsResponse `json:"ratings"`
+	Sessions            int                     `json:"sessions"` // completed sessions with focus or distractions recorded
+	AverageFocus        *float64                `json:"averageFocus,omitempty"`
+	AverageDistractions *float64                `json:"averageDistractions,omitempty"`
+}
+
+// ToLocationReviewResponse converts a LocationReview entity to a response DTO
+func ToLocationReviewResponse(review *entity.LocationReview) LocationReviewResponse {
+	return LocationReviewResponse{
+		ID:         review.ID.Hex(),
+		LocationID: review.LocationID.Hex(),
+		UserID:     review.UserID.Hex(),
+		Noise:      review.Noise,
+		WiFi:       review.WiFi,
+		Outlets:    review.Outlets,
+		Seating:    review.Seating,
+		Crowding:   review.Crowding,
+		Text:       review.Text,
+		CreatedAt:  review.CreatedAt,
+		UpdatedAt:  review.UpdatedAt,
+	}
+}
+
+// ToLocationRatingsResponse converts a spot's ratings to a response DTO. A
+// spot without reviews has nil ratings.
+func ToLocationRatingsResponse(ratings *entity.LocationRatings) LocationRatingsResponse {
+	if ratings == nil {
+		return LocationRatingsResponse{}
+	}
+
+	return LocationRatingsResponse{
+		Reviews:  ratings.Reviews,
+		Noise:    ratings.Noise,
+		WiFi:     ratings.WiFi,
+		Outlets:  ratings.Outlets,
+		Seating:  ratings.Seating,
+		Crowding: ratings.Crowding,
+	}
+}
+
+// ToRatingCorrelationResponse converts an aspect's correlations to a
+// response DTO
+func ToRatingCorrelationResponse(correlation entity.RatingCorrelation) RatingCorrelationResponse {
+	return RatingCorrelationResponse{
+		Aspect:       string(correlation.Aspect),
+		Focus:        toCorrelationResponse(correlation.Focus),
+		Distractions: toCorrelationResponse(correlation.Distractions),
+	}
+}
+
+// ToSpotFitResponse converts how the user focuses at a spot to a response
+// DTO
+func ToSpotFitResponse(location *entity.Location, fit entity.SpotFit) SpotFitResponse {
+	return SpotFitResponse{
+		Location:            ToLocationResponse(location),
+		Ratings:             ToLocationRatingsResponse(fit.Ratings),
+		Sessions:            fit.Sessions,
+		AverageFocus:        fit.AverageFocus,
+		AverageDistractions: fit.AverageDistractions,
+	}
+}
+
+func toCorrelationResponse(correlation entity.Correlation) CorrelationResponse {
+	return CorrelationResponse{
+		Coefficient: correlation.Coefficient,
+		PValue:      correlation.PValue,
+		Spots:       correlation.Spots,
+		Sessions:    correlation.Sessions,
+	}
+}
@@ -0,0 +1,23 @@
+package dto
+
+// SessionSearchResponse lists the sessions matching a search, best match first
+type SessionSearchResponse struct {
+	Query   string                        `json:"query"`
+	Results []SessionSearchResultResponse `json:"results"`
+	Total   int                           `json:"total"`
+	Limit   int                           `json:"limit"`
+	Offset  int                           `json:"offset"`
+}
+
+type SessionSearchResultResponse struct {
+	Session  FocusSessionResponse    `json:"session"`
+	Score    float64                 `json:"score"`
+	Snippets []SearchSnippetResponse `json:"snippets,omitempty"`
+}
+
+// SearchSnippetResponse is an excerpt of a matching field. Text is HTML
+// escaped, with matched words wrapped in <mark> tags.
+type SearchSnippetResponse struct {
+	Field string `json:"field"` // title, description or notes
+	Text  string `json:"text"`
+}
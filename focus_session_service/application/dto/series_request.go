@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+type CreateSeriesRequest struct {
+	Title           string                  `json:"title" validate:"required"`
+	Description     string                  `json:"description"`
+	RRule           string                  `json:"rrule" validate:"required"`     // e.g. FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR
+	StartTime       time.Time               `json:"startTime" validate:"required"` // first occurrence
+	Timezone        string                  `json:"timezone,omitempty"`            // IANA name, default UTC
+	Duration        int                     `json:"duration" validate:"required,min=1"`
+	LocationID      string                  `json:"locationId,omitempty"`
+	LocationDetails *LocationDetailsRequest `json:"locationDetails,omitempty"`
+	Tags            []string                `json:"tags,omitempty"`
+}
+
+// UpdateSeriesRequest edits one occurrence, an occurrence and the ones after
+// it, or the whole series. OccurrenceTime is the original start of the
+// occurrence and is required unless Scope is "all".
+type UpdateSeriesRequest struct {
+	Scope           string                  `json:"scope" validate:"required,oneof=this following all"`
+	OccurrenceTime  *time.Time              `json:"occurrenceTime,omitempty"`
+	Title           string                  `json:"title,omitempty"`
+	Description     string                  `json:"description,omitempty"`
+	RRule           string                  `json:"rrule,omitempty"`
+	StartTime       *time.Time              `json:"startTime,omitempty"`
+	Duration        *int                    `json:"duration,omitempty" validate:"omitempty,min=1"`
+	LocationID      string                  `json:"locationId,omitempty"`
+	LocationDetails *LocationDetailsRequest `json:"locationDetails,omitempty"`
+	Tags            []string                `json:"tags,omitempty"`
+}
+
+type CancelSeriesRequest struct {
+	Scope          string     `json:"scope" validate:"required,oneof=this following all"`
+	OccurrenceTime *time.Time `json:"occurrenceTime,omitempty"`
+}
+
+type GetOccurrencesRequest struct {
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
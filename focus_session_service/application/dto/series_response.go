@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"focusspot/focussessionservice/domain/entity"
+	"time"
+)
+
+type SeriesResponse struct {
+	ID              string                   `json:"id"`
+	UserID          string                   `json:"userId"`
+	Title           string                   `json:"title"`
+	Description     string                   `json:"description,omitempty"`
+	RRule           string                   `json:"rrule"`
+	StartTime       time.Time                `json:"startTime"`
+	Timezone        string                   `json:"timezone"`
+	Until           *time.Time               `json:"until,omitempty"`
+	Duration        int                      `json:"duration"`
+	LocationID      string                   `json:"locationId,omitempty"`
+	LocationDetails *LocationDetailsResponse `json:"locationDetails,omitempty"`
+	Tags            []string                 `json:"tags,omitempty"`
+	ExceptionDates  []time.Time              `json:"exceptionDates,omitempty"`
+	CreatedAt       time.Time                `json:"createdAt"`
+	UpdatedAt       time.Time                `json:"updatedAt"`
+}
+
+type OccurrencesResponse struct {
+	Series   SeriesResponse         `json:"series"`
+	Sessions []FocusSessionResponse `json:"sessions"`
+	From     time.Time              `json:"from"`
+	To       time.Time              `json:"to"`
+}
+
+// SeriesChangeResponse is returned by edits and cancellations. Series is the
+// series the change ended up in, which is a new one after a "following" edit.
+type SeriesChangeResponse struct {
+	Series     SeriesResponse        `json:"series"`
+	Occurrence *FocusSessionResponse `json:"occurrence,omitempty"`
+}
+
+// ToSeriesResponse converts a SessionSeries entity to a SeriesResponse DTO
+func ToSeriesResponse(series *entity.SessionSeries) SeriesResponse {
+	response := SeriesResponse{
+		ID:             series.ID.Hex(),
+		UserID:         series.UserID.Hex(),
+		Title:          series.Title,
+		Description:    series.Description,
+		RRule:          series.RRule,
+		StartTime:      series.StartTime,
+		Timezone:       series.Timezone,
+		Until:          series.Until,
+		Duration:       series.Duration,
+		Tags:           series.Tags,
+		ExceptionDates: series.ExceptionDates,
+		CreatedAt:      series.CreatedAt,
+		UpdatedAt:      series.UpdatedAt,
+	}
+
+	if series.LocationID != nil {
+		response.LocationID = series.LocationID.Hex()
+	}
+
+	if series.LocationDetails != nil {
+		response.LocationDetails = &LocationDetailsResponse{
+			Name:      series.LocationDetails.Name,
+			Address:   series.LocationDetails.Address,
+			Latitude:  series.LocationDetails.Latitude,
+			Longitude: series.LocationDetails.Longitude,
+			Type:      series.LocationDetails.Type,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,9 @@
+package dto
+
+// UpdateStreakSettingsRequest changes the given settings. An empty rest day
+// list removes all rest days.
+type UpdateStreakSettingsRequest struct {
+	ThresholdMinutes *int     `json:"thresholdMinutes,omitempty" validate:"omitempty,gt=0"`
+	RestDays         []string `json:"restDays,omitempty"` // weekday names, e.g. "saturday"
+	Timezone         *string  `json:"timezone,omitempty"` // IANA name, e.g. "Europe/Berlin"; empty follows the user's preference
+}
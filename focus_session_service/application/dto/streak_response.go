@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"focusspot/focussessionservice/domain/entity"
+	"strings"
+)
+
+type StreakSettingsResponse struct {
+	ThresholdMinutes int      `json:"thresholdMinutes"`
+	RestDays         []string `json:"restDays"`
+	Timezone         string   `json:"timezone,omitempty"`
+}
+
+// StreakResponse describes a user's streaks. Days are in the user's
+// timezone. The current streak stays alive until a day that is not a rest day
+// ends without reaching the threshold.
+type StreakResponse struct {
+	Current           int                    `json:"current"`
+	CurrentStart      string                 `json:"currentStart,omitempty"`
+	Longest           int                    `json:"longest"`
+	LongestStart      string                 `json:"longestStart,omitempty"`
+	LongestEnd        string                 `json:"longestEnd,omitempty"`
+	LastQualifiedDate string                 `json:"lastQualifiedDate,omitempty"`
+	Timezone          string                 `json:"timezone"`
+	Today             string                 `json:"today"`
+	TodayMinutes      int                    `json:"todayMinutes"`
+	TodayQualified    bool                   `json:"todayQualified"`
+	Settings          StreakSettingsResponse `json:"settings"`
+}
+
+// ToStreakResponse converts a Streak entity to a StreakResponse DTO as of
+// the given day
+func ToStreakResponse(streak *entity.Streak, today string, todayMinutes int) StreakResponse {
+	response := StreakResponse{
+		Current:           streak.CurrentAt(today),
+		Longest:           streak.Longest,
+		LongestStart:      streak.LongestStart,
+		LongestEnd:        streak.LongestEnd,
+		LastQualifiedDate: streak.LastQualified,
+		Timezone:          streak.Timezone,
+		Today:             today,
+		TodayMinutes:      todayMinutes,
+		TodayQualified:    streak.LastQualified == today,
+		Settings: StreakSettingsResponse{
+			ThresholdMinutes: streak.Settings.ThresholdMinutes,
+			RestDays:         make([]string, 0, len(streak.Settings.RestDays)),
+			Timezone:         streak.Settings.Timezone,
+		},
+	}
+
+	if response.Current > 0 {
+		response.CurrentStart = streak.CurrentStart
+	}
+
+	for _, day := range streak.Settings.RestDays {
+		response.Settings.RestDays = append(response.Settings.RestDays, strings.ToLower(day.String()))
+	}
+
+	return response
+}
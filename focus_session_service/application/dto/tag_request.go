@@ -0,0 +1,13 @@
+package dto
+
+// UpdateTagRequest renames a tag or changes its color. An empty color clears it.
+type UpdateTagRequest struct {
+	Name  string  `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"` // #RGB or #RRGGBB
+}
+
+// MergeTagsRequest replaces the source tags with the target on every session
+type MergeTagsRequest struct {
+	Sources []string `json:"sources" validate:"required,min=1"`
+	Target  string   `json:"target" validate:"required"`
+}
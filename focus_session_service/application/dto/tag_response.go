@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type TagResponse struct {
+	Name     string     `json:"name"`
+	Color    string     `json:"color,omitempty"`
+	Sessions int        `json:"sessions"`
+	Minutes  int        `json:"minutes"` // actual duration of completed sessions
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+}
+
+type TagsListResponse struct {
+	Tags []TagResponse `json:"tags"`
+}
+
+// TagChangeResponse reports the result of renaming, merging or deleting tags
+type TagChangeResponse struct {
+	Name            string `json:"name,omitempty"`
+	Color           string `json:"color,omitempty"`
+	SessionsUpdated int    `json:"sessionsUpdated"`
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IAchievementUseCase interface {
+	GetAchievements(ctx context.Context, userID string) (*dto.AchievementsListResponse, error)
+
+	// Achievements are evaluated when a session is completed
+	interfaces.ISessionObserver
+}
+
+type achievementUseCase struct {
+	rules           []entity.AchievementRule
+	achievementRepo interfaces.IAchievementRepository
+	sessionRepo     interfaces.IFocusSessionRepository
+	streakUseCase   IStreakUseCase
+}
+
+func NewAchievementUseCase(
+	rules []entity.AchievementRule,
+	achievementRepo interfaces.IAchievementRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+	streakUseCase IStreakUseCase,
+) IAchievementUseCase {
+	return &achievementUseCase{
+		rules:           rules,
+		achievementRepo: achievementRepo,
+		sessionRepo:     sessionRepo,
+		streakUseCase:   streakUseCase,
+	}
+}
+
+// GetAchievements lists every achievement with the user's progress, in the
+// order the rules are declared
+func (uc *achievementUseCase) GetAchievements(ctx context.Context, userID string) (*dto.AchievementsListResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	unlocked, err := uc.unlocked(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AchievementsListResponse{
+		Achievements: make([]dto.AchievementResponse, 0, len(uc.rules)),
+		Total:        len(uc.rules),
+	}
+
+	progress := uc.newProgress(userObjID)
+	for _, rule := range uc.rules {
+		current, err := progress.measure(ctx, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		achievement := unlocked[rule.Key]
+		if achievement != nil {
+			response.Unlocked++
+		}
+		response.Achievements = append(response.Achievements, dto.ToAchievementResponse(rule, current, achievement))
+	}
+
+	return response, nil
+}
+
+// OnSessionChanged unlocks the achievements a newly completed session
+// reaches. Only rules the session counts towards are measured.
+func (uc *achievementUseCase) OnSessionChanged(ctx context.Context, before, after *entity.FocusSession) {
+	if after == nil || after.Status != entity.StatusCompleted {
+		return
+	}
+	if before != nil && before.Status == entity.StatusCompleted {
+		return
+	}
+
+	if err := uc.evaluate(ctx, after.UserID, []*entity.FocusSession{after}); err != nil {
+		log.Printf("Failed to evaluate achievements: %v", err)
+	}
+}
+
+// OnSessionsImported evaluates the achievements once for all the completed
+// sessions of an import
+func (uc *achievementUseCase) OnSessionsImported(ctx context.Context, userID primitive.ObjectID, sessions []*entity.FocusSession) {
+	var completed []*entity.FocusSession
+	for _, session := range sessions {
+		if session.Status == entity.StatusCompleted {
+			completed = append(completed, session)
+		}
+	}
+	if len(completed) == 0 {
+		return
+	}
+
+	if err := uc.evaluate(ctx, userID, completed); err != nil {
+		log.Printf("Failed to evaluate achievements: %v", err)
+	}
+}
+
+// evaluate unlocks the achievements the newly completed sessions reach. Each
+// rule is measured once, and an unlock is credited to the last session that
+// counts towards it.
+func (uc *achievementUseCase) evaluate(ctx context.Context, userID primitive.ObjectID, sessions []*entity.FocusSession) error {
+	unlocked, err := uc.unlocked(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	progress := uc.newProgress(userID)
+	for _, rule := range uc.rules {
+		if unlocked[rule.Key] != nil {
+			continue
+		}
+
+		var session *entity.FocusSession
+		for _, candidate := range sessions {
+			if rule.Matches(candidate) {
+				session = candidate
+			}
+		}
+		if session == nil {
+			continue
+		}
+
+		current, err := progress.measure(ctx, rule)
+		if err != nil {
+			return err
+		}
+		if current < rule.Target {
+			continue
+		}
+
+		sessionID := session.ID
+		if err := uc.achievementRepo.Unlock(ctx, &entity.Achievement{
+			UserID:     userID,
+			Key:        rule.Key,
+			SessionID:  &sessionID,
+			UnlockedAt: time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *achievementUseCase) unlocked(ctx context.Context, userID primitive.ObjectID) (map[string]*entity.Achievement, error) {
+	achievements, err := uc.achievementRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked := make(map[string]*entity.Achievement, len(achievements))
+	for _, achievement := range achievements {
+		unlocked[achievement.Key] = achievement
+	}
+	return unlocked, nil
+}
+
+func (uc *achievementUseCase) newProgress(userID primitive.ObjectID) *achievementProgress {
+	return &achievementProgress{uc: uc, userID: userID, longestStreak: -1}
+}
+
+// achievementProgress measures rules for one user, reading the longest
+// streak at most once
+type achievementProgress struct {
+	uc            *achievementUseCase
+	userID        primitive.ObjectID
+	longestStreak int
+}
+
+func (p *achievementProgress) measure(ctx context.Context, rule entity.AchievementRule) (int, error) {
+	if rule.Metric == entity.AchievementStreakDays {
+		if p.longestStreak < 0 {
+			streaks, err := p.uc.streakUseCase.GetStreaks(ctx, p.userID.Hex())
+			if err != nil {
+				return 0, err
+			}
+			p.longestStreak = streaks.Longest
+		}
+		return p.longestStreak, nil
+	}
+
+	totals, err := p.uc.sessionRepo.GetTotals(ctx, p.userID, rule.SessionFilter())
+	if err != nil {
+		return 0, err
+	}
+
+	if rule.Metric == entity.AchievementFocusMinutes {
+		return totals.Minutes, nil
+	}
+	return totals.Sessions, nil
+}
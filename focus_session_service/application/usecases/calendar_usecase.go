@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"focusspot/focussessionservice/utils/ical"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// CalendarFeedPath is the public route prefix of subscription feeds
+	CalendarFeedPath = "/api/v1/calendar/feeds/"
+
+	// CalendarUIDSuffix marks the UIDs of events exported from FocusSpot
+	CalendarUIDSuffix = "@focusspot"
+
+	calendarProdID = "-//FocusSpot//Focus Sessions//EN"
+
+	// Default feed window around today
+	calendarPastWindow   = 90 * 24 * time.Hour
+	calendarFutureWindow = 180 * 24 * time.Hour
+)
+
+var ErrInvalidCalendarToken = errors.New("invalid calendar feed token")
+
+type ICalendarUseCase interface {
+	ExportCalendar(ctx context.Context, userID string, req dto.CalendarExportRequest) ([]byte, error)
+	ExportCalendarByToken(ctx context.Context, token string, req dto.CalendarExportRequest) ([]byte, error)
+	GetSubscription(ctx context.Context, userID string) (*dto.CalendarSubscriptionResponse, error)
+	CreateSubscription(ctx context.Context, userID string) (*dto.CalendarSubscriptionResponse, error)
+	DeleteSubscription(ctx context.Context, userID string) error
+}
+
+type calendarUseCase struct {
+	sessionRepo     interfaces.IFocusSessionRepository
+	feedRepo        interfaces.ICalendarFeedRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+}
+
+func NewCalendarUseCase(
+	sessionRepo interfaces.IFocusSessionRepository,
+	feedRepo interfaces.ICalendarFeedRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+) ICalendarUseCase {
+	return &calendarUseCase{
+		sessionRepo:     sessionRepo,
+		feedRepo:        feedRepo,
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+func (uc *calendarUseCase) ExportCalendar(ctx context.Context, userID string, req dto.CalendarExportRequest) ([]byte, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	return uc.render(ctx, userObjID, req)
+}
+
+func (uc *calendarUseCase) ExportCalendarByToken(ctx context.Context, token string, req dto.CalendarExportRequest) ([]byte, error) {
+	if token == "" {
+		return nil, ErrInvalidCalendarToken
+	}
+
+	feed, err := uc.feedRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, ErrInvalidCalendarToken
+	}
+
+	return uc.render(ctx, feed.UserID, req)
+}
+
+func (uc *calendarUseCase) GetSubscription(ctx context.Context, userID string) (*dto.CalendarSubscriptionResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	feed, err := uc.feedRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	if feed == nil {
+		return nil, errors.New("no calendar subscription found")
+	}
+
+	return toCalendarSubscriptionResponse(feed), nil
+}
+
+// CreateSubscription issues a new feed token. Any previous subscription URL
+// stops working.
+func (uc *calendarUseCase) CreateSubscription(ctx context.Context, userID string) (*dto.CalendarSubscriptionResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &entity.CalendarFeed{
+		UserID:    userObjID,
+		Token:     token,
+		CreatedAt: time.Now(),
+	}
+
+	if err := uc.feedRepo.Upsert(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	return toCalendarSubscriptionResponse(feed), nil
+}
+
+func (uc *calendarUseCase) DeleteSubscription(ctx context.Context, userID string) error {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	return uc.feedRepo.Delete(ctx, userObjID)
+}
+
+func (uc *calendarUseCase) render(ctx context.Context, userID primitive.ObjectID, req dto.CalendarExportRequest) ([]byte, error) {
+	now := time.Now()
+	startDate := now.Add(-calendarPastWindow)
+	endDate := now.Add(calendarFutureWindow)
+
+	loc, err := userLocation(ctx, uc.preferencesRepo, userID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := parseDateRange(req.From, req.To, loc)
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() {
+		startDate = from
+	}
+	if !to.IsZero() {
+		endDate = to
+	}
+
+	if endDate.Before(startDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	sessions, err := uc.sessionRepo.GetSessionsByDateRange(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   "FocusSpot sessions",
+		Events: make([]ical.Event, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		if session.Status == entity.StatusCancelled {
+			continue
+		}
+		calendar.Events = append(calendar.Events, toCalendarEvent(session))
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, calendar); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// toCalendarEvent maps a session to a VEVENT. Planned sessions are tentative;
+// running and completed ones are confirmed.
+func toCalendarEvent(session *entity.FocusSession) ical.Event {
+	end := session.StartTime.Add(time.Duration(session.Duration) * time.Minute)
+	if session.EndTime != nil {
+		end = *session.EndTime
+	}
+
+	status := ical.StatusConfirmed
+	if session.Status == entity.StatusPlanned {
+		status = ical.StatusTentative
+	}
+
+	description := session.Description
+	if session.Notes != "" {
+		description = strings.TrimSpace(description + "\n\n" + session.Notes)
+	}
+
+	event := ical.Event{
+		UID:          session.ID.Hex() + CalendarUIDSuffix,
+		Summary:      session.Title,
+		Description:  description,
+		Categories:   session.Tags,
+		Status:       status,
+		Start:        session.StartTime,
+		End:          end,
+		Created:      session.CreatedAt,
+		LastModified: session.UpdatedAt,
+	}
+
+	if session.LocationDetails != nil {
+		parts := []string{}
+		if session.LocationDetails.Name != "" {
+			parts = append(parts, session.LocationDetails.Name)
+		}
+		if session.LocationDetails.Address != "" {
+			parts = append(parts, session.LocationDetails.Address)
+		}
+		event.Location = strings.Join(parts, ", ")
+
+		if session.LocationDetails.Latitude != 0 || session.LocationDetails.Longitude != 0 {
+			event.Geo = &ical.Geo{
+				Latitude:  session.LocationDetails.Latitude,
+				Longitude: session.LocationDetails.Longitude,
+			}
+		}
+	}
+
+	return event
+}
+
+func toCalendarSubscriptionResponse(feed *entity.CalendarFeed) *dto.CalendarSubscriptionResponse {
+	return &dto.CalendarSubscriptionResponse{
+		Token:     feed.Token,
+		Path:      CalendarFeedPath + feed.Token + ".ics",
+		CreatedAt: feed.CreatedAt,
+	}
+}
+
+func newFeedToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
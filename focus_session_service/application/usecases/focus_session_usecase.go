@@ -2,10 +2,14 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"focusspot/focussessionservice/application/dto"
 	"focusspot/focussessionservice/domain/entity"
 	"focusspot/focussessionservice/domain/interfaces"
+	"focusspot/focussessionservice/utils/snippet"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -20,29 +24,63 @@ var (
 	ErrInvalidDateRange           = errors.New("invalid date range")
 	ErrInvalidDuration            = errors.New("invalid duration")
 	ErrAlreadyHaveActiveSession   = errors.New("you already have an active session")
+	ErrSessionNotActive           = errors.New("only active sessions can be paused")
+	ErrSessionNotPaused           = errors.New("only paused sessions can be resumed")
+	ErrPauseStatusChange          = errors.New("use /pause and /resume to pause or resume a session")
+	ErrInvalidSessionMode         = errors.New("invalid session mode (use single or pomodoro)")
+	ErrInvalidLocationID          = errors.New("invalid location ID")
+	ErrInvalidSessionStatus       = errors.New("invalid session status")
+	ErrInvalidSortField           = errors.New("invalid sort field")
+	ErrInvalidSortOrder           = errors.New("invalid sort order (use asc or desc)")
+	ErrInvalidTagMatch            = errors.New("invalid tag match (use any or all)")
+	ErrInvalidRange               = errors.New("invalid range: minimum is greater than maximum")
+	ErrInvalidCursor              = errors.New("invalid cursor")
+	ErrInvalidTrendPeriod         = errors.New("invalid trend period (use daily, weekly, monthly, quarterly or yearly)")
+	ErrTooManyTrendPeriods        = errors.New("too many trend periods (shorten the range or use a longer period)")
+	ErrCursorRequiresStartTime    = errors.New("cursor pagination requires sorting by startTime")
+	ErrEmptySearchQuery           = errors.New("search query is required")
+	ErrInvalidHeatmapYear         = errors.New("invalid heatmap year")
 )
 
 type IFocusSessionUseCase interface {
 	CreateSession(ctx context.Context, userID string, req dto.CreateSessionRequest) (*dto.FocusSessionResponse, error)
 	GetSessionByID(ctx context.Context, id string, userID string) (*dto.FocusSessionResponse, error)
 	GetUserSessions(ctx context.Context, userID string, req dto.GetSessionsRequest) (*dto.SessionsListResponse, error)
+	SearchSessions(ctx context.Context, userID string, req dto.SearchSessionsRequest) (*dto.SessionSearchResponse, error)
 	GetActiveSession(ctx context.Context, userID string) (*dto.FocusSessionResponse, error)
 	UpdateSession(ctx context.Context, id string, userID string, req dto.UpdateSessionRequest) (*dto.FocusSessionResponse, error)
 	StartSession(ctx context.Context, id string, userID string) (*dto.FocusSessionResponse, error)
+	PauseSession(ctx context.Context, id string, userID string) (*dto.FocusSessionResponse, error)
+	ResumeSession(ctx context.Context, id string, userID string) (*dto.FocusSessionResponse, error)
 	EndSession(ctx context.Context, id string, userID string, req dto.EndSessionRequest) (*dto.FocusSessionResponse, error)
 	CancelSession(ctx context.Context, id string, userID string) (*dto.FocusSessionResponse, error)
 	DeleteSession(ctx context.Context, id string, userID string) error
 	GetProductivityStats(ctx context.Context, userID string, req dto.GetProductivityStatsRequest) (*dto.ProductivityStatsResponse, error)
 	GetProductivityTrends(ctx context.Context, userID string, req dto.GetProductivityTrendsRequest) (*dto.ProductivityTrendsResponse, error)
+	GetProductivityHeatmap(ctx context.Context, userID string, req dto.GetProductivityHeatmapRequest) (*dto.ProductivityHeatmapResponse, error)
 }
 
 type focusSessionUseCase struct {
-	sessionRepo interfaces.IFocusSessionRepository
+	sessionRepo     interfaces.IFocusSessionRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+	rollupRepo      interfaces.IDailyRollupRepository
+	locationRepo    interfaces.ILocationRepository
+	observers       sessionObservers
 }
 
-func NewFocusSessionUseCase(sessionRepo interfaces.IFocusSessionRepository) IFocusSessionUseCase {
+func NewFocusSessionUseCase(
+	sessionRepo interfaces.IFocusSessionRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+	rollupRepo interfaces.IDailyRollupRepository,
+	locationRepo interfaces.ILocationRepository,
+	observers ...interfaces.ISessionObserver,
+) IFocusSessionUseCase {
 	return &focusSessionUseCase{
-		sessionRepo: sessionRepo,
+		sessionRepo:     sessionRepo,
+		preferencesRepo: preferencesRepo,
+		rollupRepo:      rollupRepo,
+		locationRepo:    locationRepo,
+		observers:       observers,
 	}
 }
 
@@ -52,25 +90,32 @@ func (uc *focusSessionUseCase) CreateSession(ctx context.Context, userID string,
 		return nil, errors.New("invalid user ID")
 	}
 
-	var locationObjID *primitive.ObjectID
-	if req.LocationID != "" {
-		id, err := primitive.ObjectIDFromHex(req.LocationID)
-		if err != nil {
-			return nil, errors.New("invalid location ID")
-		}
+	locationObjID, locationDetails, err := sessionLocation(ctx, uc.locationRepo, userObjID, req.LocationID, req.LocationDetails)
+	if err != nil {
+		return nil, err
+	}
 
-		locationObjID = &id
+	mode := entity.SessionMode(req.Mode)
+	if mode == "" {
+		mode = entity.ModeSingle
 	}
 
-	var locationDetails *entity.LocationDetails
-	if req.LocationDetails != nil {
-		locationDetails = &entity.LocationDetails{
-			Name:      req.LocationDetails.Name,
-			Address:   req.LocationDetails.Address,
-			Latitude:  req.LocationDetails.Latitude,
-			Longitude: req.LocationDetails.Longitude,
-			Type:      req.LocationDetails.Type,
+	var pomodoro *entity.PomodoroState
+	switch mode {
+	case entity.ModeSingle:
+	case entity.ModePomodoro:
+		config := dto.PomodoroConfigRequest{}
+		if req.Pomodoro != nil {
+			config = *req.Pomodoro
 		}
+		pomodoro = entity.NewPomodoroState(config.WorkDuration, config.ShortBreakDuration, config.LongBreakDuration, config.Cycles)
+
+		// Default the planned duration to one full set of pomodoros
+		if req.Duration <= 0 {
+			req.Duration = pomodoro.SetDuration()
+		}
+	default:
+		return nil, ErrInvalidSessionMode
 	}
 
 	now := time.Now()
@@ -82,11 +127,14 @@ func (uc *focusSessionUseCase) CreateSession(ctx context.Context, userID string,
 		StartTime:       req.StartTime,
 		Duration:        req.Duration,
 		Status:          entity.StatusPlanned,
+		Mode:            mode,
+		Pomodoro:        pomodoro,
 		LocationID:      locationObjID,
 		LocationDetails: locationDetails,
 		Tags:            req.Tags,
 		CreatedAt:       now,
 		UpdatedAt:       now,
+		Active:          true,
 	}
 
 	// Check if start time is in the future
@@ -120,7 +168,12 @@ func (uc *focusSessionUseCase) GetSessionByID(ctx context.Context, id string, us
 		return nil, ErrNoSessionFoundAccessDenied
 	}
 
-	response := dto.ToFocusSessionResponse(session)
+	scorer, err := userScorer(ctx, uc.preferencesRepo, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToScoredFocusSessionResponse(session, scorer)
 	return &response, nil
 }
 
@@ -130,51 +183,293 @@ func (uc *focusSessionUseCase) GetUserSessions(ctx context.Context, userID strin
 		return nil, ErrInvalidUserID
 	}
 
-	var sessions []*entity.FocusSession
+	loc, scorer, err := userPreferences(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if date range is provided in the request
-	if req.StartDate != "" && req.EndDate != "" {
-		startDate, err := time.Parse("2006-01-02", req.StartDate)
-		if err != nil {
-			return nil, ErrInvalidDateRange
-		}
-		endDate, err := time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
-			return nil, ErrInvalidDateRange
-		}
+	filter, err := toSessionFilter(req, loc)
+	if err != nil {
+		return nil, err
+	}
 
-		// Make endDate inclusive by setting it to the end of the day
-		endDate = endDate.Add(24 * time.Hour).Add(-1 * time.Second)
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
 
-		sessions, err = uc.sessionRepo.GetSessionsByDateRange(ctx, userObjID, startDate, endDate)
+	// One extra session tells whether there is another page
+	var sessions []*entity.FocusSession
+	var cursor *entity.SessionCursor
+	if req.Cursor != "" {
+		if filter.SortBy != entity.SortByStartTime {
+			return nil, ErrCursorRequiresStartTime
+		}
+
+		cursor, err = decodeSessionCursor(req.Cursor)
 		if err != nil {
 			return nil, err
 		}
+
+		req.Offset = 0
+		sessions, err = uc.sessionRepo.GetPageByUserID(ctx, userObjID, filter, cursor, req.Limit+1)
 	} else {
-		// Otherwise, get paginated sessions with GetByUserID using limit and offset
-		sessions, err = uc.sessionRepo.GetByUserID(ctx, userObjID, req.Limit, req.Offset)
-		if err != nil {
-			return nil, err
+		sessions, err = uc.sessionRepo.GetByUserID(ctx, userObjID, filter, req.Limit+1, req.Offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(sessions) > req.Limit
+	if hasMore {
+		if cursor != nil && cursor.Before {
+			sessions = sessions[1:]
+		} else {
+			sessions = sessions[:req.Limit]
 		}
 	}
 
+	total, err := uc.sessionRepo.CountByUserID(ctx, userObjID, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert each session entity to SessionResponse
-	var sessionResponseList []dto.FocusSessionResponse
+	sessionResponseList := make([]dto.FocusSessionResponse, 0, len(sessions))
 	for _, session := range sessions {
-		sessionResponse := dto.ToFocusSessionResponse(session)
+		sessionResponse := dto.ToScoredFocusSessionResponse(session, scorer)
 		sessionResponseList = append(sessionResponseList, sessionResponse)
 	}
 
 	// Populate the SessionsListResponse with the converted sessions and metadata
 	response := dto.SessionsListResponse{
 		Sessions: sessionResponseList,
-		Total:    len(sessionResponseList),
+		Total:    int(total),
 		Limit:    req.Limit,
 		Offset:   req.Offset,
 	}
+
+	// Cursors point at the first and last session of the page
+	if filter.SortBy == entity.SortByStartTime && len(sessions) > 0 {
+		// The side the client came from always has sessions; the other side
+		// has them when the extra session was found
+		hasNext, hasPrev := hasMore, cursor != nil || req.Offset > 0
+		if cursor != nil && cursor.Before {
+			hasNext, hasPrev = true, hasMore
+		}
+
+		if hasNext {
+			response.NextCursor = encodeSessionCursor(sessions[len(sessions)-1], false)
+		}
+		if hasPrev {
+			response.PrevCursor = encodeSessionCursor(sessions[0], true)
+		}
+	}
+
 	return &response, nil
 }
 
+// SearchSessions finds sessions by the words in their title, description and
+// notes, ranked by relevance, with an excerpt of each matching field
+func (uc *focusSessionUseCase) SearchSessions(ctx context.Context, userID string, req dto.SearchSessionsRequest) (*dto.SessionSearchResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	loc, scorer, err := userPreferences(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := toSessionFilter(req.GetSessionsRequest, loc)
+	if err != nil {
+		return nil, err
+	}
+	filter.Text = query
+
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	hits, err := uc.sessionRepo.SearchByUserID(ctx, userObjID, filter, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := uc.sessionRepo.CountByUserID(ctx, userObjID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := snippet.Terms(query)
+	results := make([]dto.SessionSearchResultResponse, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, dto.SessionSearchResultResponse{
+			Session:  dto.ToScoredFocusSessionResponse(hit.Session, scorer),
+			Score:    hit.Score,
+			Snippets: searchSnippets(hit.Session, terms),
+		})
+	}
+
+	return &dto.SessionSearchResponse{
+		Query:   query,
+		Results: results,
+		Total:   int(total),
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}, nil
+}
+
+// searchSnippetRadius is how many characters of context surround a match
+const searchSnippetRadius = 60
+
+func searchSnippets(session *entity.FocusSession, terms []string) []dto.SearchSnippetResponse {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"title", session.Title},
+		{"description", session.Description},
+		{"notes", session.Notes},
+	}
+
+	var snippets []dto.SearchSnippetResponse
+	for _, field := range fields {
+		if text, ok := snippet.Make(field.value, terms, searchSnippetRadius); ok {
+			snippets = append(snippets, dto.SearchSnippetResponse{
+				Field: field.name,
+				Text:  text,
+			})
+		}
+	}
+	return snippets
+}
+
+// sessionCursor is the JSON inside an opaque list cursor
+type sessionCursor struct {
+	StartTime time.Time `json:"t"`
+	ID        string    `json:"id"`
+	Before    bool      `json:"b,omitempty"`
+}
+
+func encodeSessionCursor(session *entity.FocusSession, before bool) string {
+	data, _ := json.Marshal(sessionCursor{
+		StartTime: session.StartTime,
+		ID:        session.ID.Hex(),
+		Before:    before,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSessionCursor(value string) (*entity.SessionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c sessionCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil || c.StartTime.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &entity.SessionCursor{
+		StartTime: c.StartTime,
+		ID:        id,
+		Before:    c.Before,
+	}, nil
+}
+
+// toSessionFilter validates the list filters of a request, reading dates in
+// loc. Sessions are listed newest first unless asked otherwise.
+func toSessionFilter(req dto.GetSessionsRequest, loc *time.Location) (entity.SessionFilter, error) {
+	filter := entity.SessionFilter{
+		Tags:         req.Tags,
+		LocationType: req.LocationType,
+		MinRating:    req.MinRating,
+		MaxRating:    req.MaxRating,
+		MinFocus:     req.MinFocus,
+		MaxFocus:     req.MaxFocus,
+		MinDuration:  req.MinDuration,
+		MaxDuration:  req.MaxDuration,
+		SortBy:       entity.SessionSortField(req.Sort),
+		SortDesc:     true,
+	}
+
+	var err error
+	filter.StartDate, filter.EndDate, err = parseDateRange(req.StartDate, req.EndDate, loc)
+	if err != nil {
+		return filter, err
+	}
+
+	for _, status := range req.Status {
+		switch entity.SessionStatus(status) {
+		case entity.StatusPlanned, entity.StatusActive, entity.StatusPaused, entity.StatusCompleted, entity.StatusCancelled:
+			filter.Statuses = append(filter.Statuses, entity.SessionStatus(status))
+		default:
+			return filter, ErrInvalidSessionStatus
+		}
+	}
+
+	switch req.TagMatch {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		return filter, ErrInvalidTagMatch
+	}
+
+	if req.LocationID != "" {
+		locationID, err := primitive.ObjectIDFromHex(req.LocationID)
+		if err != nil {
+			return filter, ErrInvalidLocationID
+		}
+		filter.LocationID = &locationID
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = entity.SortByStartTime
+	}
+	if !filter.SortBy.IsValid() {
+		return filter, ErrInvalidSortField
+	}
+
+	switch req.Order {
+	case "", "desc":
+	case "asc":
+		filter.SortDesc = false
+	default:
+		return filter, ErrInvalidSortOrder
+	}
+
+	if isInvertedRange(filter.MinRating, filter.MaxRating) ||
+		isInvertedRange(filter.MinFocus, filter.MaxFocus) ||
+		isInvertedRange(filter.MinDuration, filter.MaxDuration) {
+		return filter, ErrInvalidRange
+	}
+
+	return filter, nil
+}
+
+func isInvertedRange(low, high *int) bool {
+	return low != nil && high != nil && *low > *high
+}
+
 func (uc *focusSessionUseCase) GetActiveByUserID(ctx context.Context, userID string) (*dto.FocusSessionResponse, error) {
 	userObjID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -205,7 +500,15 @@ func (uc *focusSessionUseCase) GetActiveSession(ctx context.Context, userID stri
 	if session == nil {
 		return nil, errors.New("no active session found")
 	}
+
+	// Report the live pomodoro phase and how long it has left
+	progress := session.SyncPomodoro(time.Now())
+
 	response := dto.ToFocusSessionResponse(session)
+	if progress != nil {
+		remaining := int(progress.PhaseRemaining.Seconds())
+		response.Pomodoro.PhaseRemaining = &remaining
+	}
 	return &response, nil
 }
 
@@ -235,6 +538,8 @@ func (uc *focusSessionUseCase) UpdateSession(
 		return nil, ErrNoSessionFoundAccessDenied
 	}
 
+	before := *session
+
 	// Update only provided fields
 	if req.Title != "" {
 		session.Title = req.Title
@@ -252,21 +557,10 @@ func (uc *focusSessionUseCase) UpdateSession(
 		session.Duration = *req.Duration
 	}
 
-	if req.LocationID != "" {
-		locID, err := primitive.ObjectIDFromHex(req.LocationID)
+	if req.LocationID != "" || req.LocationDetails != nil {
+		session.LocationID, session.LocationDetails, err = sessionLocation(ctx, uc.locationRepo, userObjID, req.LocationID, req.LocationDetails)
 		if err != nil {
-			return nil, errors.New("invalid location ID")
-		}
-		session.LocationID = &locID
-	}
-
-	if req.LocationDetails != nil {
-		session.LocationDetails = &entity.LocationDetails{
-			Name:      req.LocationDetails.Name,
-			Address:   req.LocationDetails.Address,
-			Latitude:  req.LocationDetails.Latitude,
-			Longitude: req.LocationDetails.Longitude,
-			Type:      req.LocationDetails.Type,
+			return nil, err
 		}
 	}
 
@@ -275,7 +569,13 @@ func (uc *focusSessionUseCase) UpdateSession(
 	}
 
 	if req.Status != "" {
-		session.Status = entity.SessionStatus(req.Status)
+		// Pausing and resuming open and close pause intervals, which a plain
+		// status change would leave inconsistent
+		status := entity.SessionStatus(req.Status)
+		if status != session.Status && (status == entity.StatusPaused || session.Status == entity.StatusPaused) {
+			return nil, ErrPauseStatusChange
+		}
+		session.Status = status
 	}
 
 	session.UpdatedAt = time.Now()
@@ -283,8 +583,14 @@ func (uc *focusSessionUseCase) UpdateSession(
 	if err := uc.sessionRepo.Update(ctx, session); err != nil {
 		return nil, err
 	}
+	uc.observers.notify(ctx, &before, session)
 
-	response := dto.ToFocusSessionResponse(session)
+	scorer, err := userScorer(ctx, uc.preferencesRepo, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToScoredFocusSessionResponse(session, scorer)
 	return &response, nil
 }
 
@@ -318,14 +624,100 @@ func (uc *focusSessionUseCase) StartSession(ctx context.Context, id string, user
 		return nil, ErrNoSessionFoundAccessDenied
 	}
 
-	err = uc.sessionRepo.UpdateStatus(ctx, sessionID, entity.StatusActive)
+	// Sessions start when the user starts them, not at the planned time
+	startTime := time.Now()
+	err = uc.sessionRepo.StartSession(ctx, sessionID, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Status = entity.StatusActive
+	session.UpdatedAt = time.Now()
+	session.StartTime = startTime
+
+	response := dto.ToFocusSessionResponse(session)
+	return &response, nil
+}
+
+func (uc *focusSessionUseCase) PauseSession(
+	ctx context.Context,
+	id string,
+	userID string,
+) (*dto.FocusSessionResponse, error) {
+	sessionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidSessionID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	session, err := uc.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if session.UserID != userObjID {
+		return nil, ErrNoSessionFoundAccessDenied
+	}
+
+	if session.Status != entity.StatusActive {
+		return nil, ErrSessionNotActive
+	}
+
+	pausedAt := time.Now()
+	if err := uc.sessionRepo.PauseSession(ctx, sessionID, pausedAt); err != nil {
+		return nil, err
+	}
+
+	session.Status = entity.StatusPaused
+	session.Pauses = append(session.Pauses, entity.PauseInterval{PausedAt: pausedAt})
+	session.UpdatedAt = time.Now()
+
+	response := dto.ToFocusSessionResponse(session)
+	return &response, nil
+}
+
+func (uc *focusSessionUseCase) ResumeSession(
+	ctx context.Context,
+	id string,
+	userID string,
+) (*dto.FocusSessionResponse, error) {
+	sessionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidSessionID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	session, err := uc.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if session.UserID != userObjID {
+		return nil, ErrNoSessionFoundAccessDenied
+	}
+
+	if session.Status != entity.StatusPaused || !session.IsPaused() {
+		return nil, ErrSessionNotPaused
+	}
+
+	resumedAt := time.Now()
+	if err := uc.sessionRepo.ResumeSession(ctx, sessionID, resumedAt); err != nil {
 		return nil, err
 	}
 
 	session.Status = entity.StatusActive
+	session.Pauses[len(session.Pauses)-1].ResumedAt = &resumedAt
 	session.UpdatedAt = time.Now()
-	session.StartTime = time.Now()
 
 	response := dto.ToFocusSessionResponse(session)
 	return &response, nil
@@ -357,11 +749,14 @@ func (uc *focusSessionUseCase) EndSession(
 		return nil, ErrNoSessionFoundAccessDenied
 	}
 
-	// Only active session can be ended
-	if session.Status != entity.StatusActive {
+	// Only active or paused session can be ended
+	if session.Status != entity.StatusActive && session.Status != entity.StatusPaused {
 		return nil, errors.New("only active session can be ended")
 	}
 
+	before := *session
+	before.Pauses = append([]entity.PauseInterval(nil), session.Pauses...)
+
 	endTime := time.Now()
 	err = uc.sessionRepo.EndSession(
 		ctx,
@@ -388,11 +783,23 @@ func (uc *focusSessionUseCase) EndSession(
 	session.Mood = req.Mood
 	session.Distractions = req.Distractions
 
-	// Calculate actual duration in minutes
-	duration := int(endTime.Sub(session.StartTime).Minutes())
+	// Close an open pause so it ends with the session
+	if session.IsPaused() {
+		session.Pauses[len(session.Pauses)-1].ResumedAt = &endTime
+	}
+
+	// Calculate actual duration in minutes, leaving out paused time
+	duration := session.FocusedMinutes(endTime)
 	session.ActualDuration = &duration
+	session.SyncPomodoro(endTime)
+	uc.observers.notify(ctx, &before, session)
 
-	response := dto.ToFocusSessionResponse(session)
+	scorer, err := userScorer(ctx, uc.preferencesRepo, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToScoredFocusSessionResponse(session, scorer)
 	return &response, nil
 }
 
@@ -465,7 +872,12 @@ func (uc *focusSessionUseCase) DeleteSession(ctx context.Context, id string, use
 		return ErrNoSessionFoundAccessDenied
 	}
 
-	return uc.sessionRepo.Delete(ctx, sessionID)
+	if err := uc.sessionRepo.Delete(ctx, sessionID); err != nil {
+		return err
+	}
+	uc.observers.notify(ctx, session, nil)
+
+	return nil
 }
 
 func (uc *focusSessionUseCase) GetProductivityStats(
@@ -478,28 +890,25 @@ func (uc *focusSessionUseCase) GetProductivityStats(
 		return nil, ErrInvalidUserID
 	}
 
+	loc, scorer, err := userPreferences(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
 	var startDate, endDate time.Time
 
 	// Default to last 30 days if not specified
 	if req.StartDate == "" || req.EndDate == "" {
-		endDate = time.Now()
+		endDate = time.Now().In(loc)
 		startDate = endDate.AddDate(0, 0, -30)
 	} else {
-		startDate, err = time.Parse("2006-01-02", req.StartDate)
+		startDate, endDate, err = parseDateRange(req.StartDate, req.EndDate, loc)
 		if err != nil {
-			return nil, errors.New("invalid start date format (use YYYY-MM-DD)")
-		}
-
-		endDate, err = time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
-			return nil, errors.New("invalid end date format (use YYYY-MM-DD)")
+			return nil, err
 		}
-
-		// Make endDate inclusive by setting it to the end of the day
-		endDate = endDate.Add(24 * time.Hour).Add(-1 * time.Second)
 	}
 
-	stats, err := uc.sessionRepo.GetProductivityStats(ctx, userObjID, startDate, endDate)
+	stats, err := uc.sessionRepo.GetProductivityStats(ctx, userObjID, startDate, endDate, loc, scorer)
 
 	if err != nil {
 		return nil, err
@@ -508,9 +917,11 @@ func (uc *focusSessionUseCase) GetProductivityStats(
 	dateRange := dto.DateRange{
 		StartDate: startDate,
 		EndDate:   endDate,
+		Timezone:  loc.String(),
 	}
 
 	response := dto.ToProductivityStatsResponse(stats, dateRange)
+	response.ScoreVersion = scorer.Version()
 	return &response, nil
 }
 
@@ -524,23 +935,227 @@ func (uc *focusSessionUseCase) GetProductivityTrends(ctx context.Context, userID
 		req.Period = entity.Weekly
 	}
 
-	if req.Limit <= 0 {
-		req.Limit = 12
+	if !req.Period.IsValid() {
+		return nil, ErrInvalidTrendPeriod
 	}
 
-	trends, err := uc.sessionRepo.GetProductivityTrends(ctx, userObjID, req.Period)
+	loc, scorer, err := userPreferences(ctx, uc.preferencesRepo, userObjID, req.Timezone)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.ProductivityTrendsResponse{
-		Period:       trends.Period,
-		Dates:        trends.Dates,
-		Durations:    trends.Durations,
-		Ratings:      trends.Ratings,
-		Focus:        trends.Focus,
-		Energy:       trends.Energy,
-		Mood:         trends.Mood,
-		Productivity: trends.Productivity,
-	}, nil
+	startDate, endDate, err := parseDateRange(req.From, req.To, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err = trendWindow(req.Period, startDate, endDate, req.Limit, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	trends, err := uc.productivityTrends(ctx, userObjID, req.Period, startDate, endDate, loc, scorer)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToProductivityTrendsResponse(trends, dto.DateRange{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Timezone:  loc.String(),
+	})
+	response.ScoreVersion = scorer.Version()
+
+	if req.Compare {
+		// The previous window has as many periods, and ends where this one
+		// starts
+		periods := len(trends.Starts)
+		previousStart := req.Period.Add(startDate, -periods)
+		previousEnd := req.Period.Add(endDate, -periods)
+		if !previousEnd.Before(startDate) {
+			previousEnd = startDate.Add(-time.Second)
+		}
+
+		previous, err := uc.productivityTrends(ctx, userObjID, req.Period, previousStart, previousEnd, loc, scorer)
+		if err != nil {
+			return nil, err
+		}
+
+		comparison := dto.ToTrendsComparison(trends, previous, dto.DateRange{
+			StartDate: previousStart,
+			EndDate:   previousEnd,
+			Timezone:  loc.String(),
+		})
+		response.Comparison = &comparison
+	}
+
+	return &response, nil
+}
+
+func (uc *focusSessionUseCase) GetProductivityHeatmap(ctx context.Context, userID string, req dto.GetProductivityHeatmapRequest) (*dto.ProductivityHeatmapResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	loc, scorer, err := userPreferences(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Year == 0 {
+		req.Year = time.Now().In(loc).Year()
+	}
+	if req.Year < 1970 || req.Year > 9999 {
+		return nil, ErrInvalidHeatmapYear
+	}
+
+	startDate := time.Date(req.Year, time.January, 1, 0, 0, 0, 0, loc)
+	endDate := startDate.AddDate(1, 0, 0).Add(-time.Second)
+
+	rollups, err := uc.dailyRollups(ctx, userObjID, startDate, endDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := entity.ComputeProductivityHeatmap(rollups, req.Year, loc, scorer.Version())
+
+	response := dto.ToProductivityHeatmapResponse(heatmap, dto.DateRange{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Timezone:  loc.String(),
+	})
+	response.ScoreVersion = scorer.Version()
+
+	return &response, nil
+}
+
+// dailyRollups returns the rollups of the days from startDate to endDate in
+// loc. They are read from the stored rollups when those are in loc, and
+// rolled up from the sessions otherwise.
+func (uc *focusSessionUseCase) dailyRollups(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time, loc *time.Location) ([]*entity.DailyRollup, error) {
+	timezone, err := uc.rollupRepo.GetTimezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if timezone == loc.String() {
+		return uc.rollupRepo.GetRange(
+			ctx,
+			userID,
+			startDate.In(loc).Format(entity.RollupDateLayout),
+			endDate.In(loc).Format(entity.RollupDateLayout),
+		)
+	}
+
+	sessions, err := uc.sessionRepo.GetSessionsByDateRange(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.ComputeDailyRollups(sessions, loc), nil
+}
+
+// Windows at least this long are read from the daily rollups when they can be
+const rollupTrendsMinWindow = 90 * 24 * time.Hour
+
+// productivityTrends reads long windows from the daily rollups, when they are
+// in loc and the scorer is built in. Rollups hold whole days, so the window
+// must start at midnight, and its last day, which may end before midnight,
+// is rolled up from its sessions instead. Other windows are aggregated from
+// the sessions.
+func (uc *focusSessionUseCase) productivityTrends(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	period entity.Period,
+	startDate, endDate time.Time,
+	loc *time.Location,
+	scorer entity.ProductivityScorer,
+) (*entity.ProductivityTrends, error) {
+	startDate, endDate = startDate.In(loc), endDate.In(loc)
+	lastDay := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, loc)
+
+	builtIn, ok := entity.ScorerByName(scorer.Name())
+	useRollups := ok && builtIn.Version() == scorer.Version() &&
+		endDate.Sub(startDate) >= rollupTrendsMinWindow &&
+		startDate.Equal(time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc))
+
+	if useRollups {
+		timezone, err := uc.rollupRepo.GetTimezone(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		useRollups = timezone == loc.String()
+	}
+
+	if !useRollups {
+		return uc.sessionRepo.GetProductivityTrends(ctx, userID, period, startDate, endDate, scorer)
+	}
+
+	rollups, err := uc.rollupRepo.GetRange(
+		ctx,
+		userID,
+		startDate.Format(entity.RollupDateLayout),
+		lastDay.AddDate(0, 0, -1).Format(entity.RollupDateLayout),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := uc.sessionRepo.GetSessionsByDateRange(ctx, userID, lastDay, endDate)
+	if err != nil {
+		return nil, err
+	}
+	rollups = append(rollups, entity.ComputeDailyRollups(sessions, loc)...)
+
+	return entity.ComputeProductivityTrendsFromRollups(rollups, period, startDate, endDate, scorer.Version()), nil
+}
+
+// Most periods in a trends window
+const maxTrendPeriods = 366
+
+// Periods in a trends window when the request gives neither a start nor a
+// limit
+var defaultTrendPeriods = map[entity.Period]int{
+	entity.Daily:     30,
+	entity.Weekly:    12,
+	entity.Monthly:   12,
+	entity.Quarterly: 8,
+	entity.Yearly:    5,
+}
+
+// trendWindow completes the window of a trends request. A missing end is now.
+// A missing start is as many periods before the end as the limit asks for,
+// and a given one must not make the window longer than the limit. No limit
+// may ask for more than maxTrendPeriods.
+func trendWindow(period entity.Period, startDate, endDate time.Time, limit int, now time.Time) (time.Time, time.Time, error) {
+	if endDate.IsZero() {
+		endDate = now
+	}
+
+	if limit > maxTrendPeriods {
+		return time.Time{}, time.Time{}, ErrTooManyTrendPeriods
+	}
+
+	if startDate.IsZero() {
+		if limit <= 0 {
+			limit = defaultTrendPeriods[period]
+		}
+		return period.Add(period.Start(endDate), 1-limit), endDate, nil
+	}
+
+	if limit <= 0 {
+		limit = maxTrendPeriods
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+
+	// The period after the last one allowed must start after the window
+	if !endDate.Before(period.Add(period.Start(startDate), limit)) {
+		return time.Time{}, time.Time{}, ErrTooManyTrendPeriods
+	}
+
+	return startDate, endDate, nil
 }
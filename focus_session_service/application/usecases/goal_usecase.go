@@ -0,0 +1,310 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidGoalID           = errors.New("invalid goal ID")
+	ErrInvalidGoalMetric       = errors.New("invalid goal metric (use focus_minutes, completed_sessions or average_focus)")
+	ErrInvalidGoalPeriod       = errors.New("invalid goal period (use daily, weekly or monthly)")
+	ErrInvalidGoalTarget       = errors.New("invalid goal target")
+	ErrGoalTitleRequired       = errors.New("goal title is required")
+	ErrNoGoalFoundAccessDenied = errors.New("no goal found or access denied")
+)
+
+type IGoalUseCase interface {
+	CreateGoal(ctx context.Context, userID string, req dto.CreateGoalRequest) (*dto.GoalResponse, error)
+	GetGoalByID(ctx context.Context, id string, userID string) (*dto.GoalResponse, error)
+	GetUserGoals(ctx context.Context, userID string) ([]dto.GoalResponse, error)
+	UpdateGoal(ctx context.Context, id string, userID string, req dto.UpdateGoalRequest) (*dto.GoalResponse, error)
+	DeleteGoal(ctx context.Context, id string, userID string) error
+	GetGoalProgress(ctx context.Context, id string, userID string, req dto.GetGoalProgressRequest) (*dto.GoalProgressResponse, error)
+	GetAllGoalProgress(ctx context.Context, userID string, req dto.GetGoalProgressRequest) ([]dto.GoalProgressResponse, error)
+}
+
+type goalUseCase struct {
+	goalRepo        interfaces.IGoalRepository
+	sessionRepo     interfaces.IFocusSessionRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+}
+
+func NewGoalUseCase(
+	goalRepo interfaces.IGoalRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+) IGoalUseCase {
+	return &goalUseCase{
+		goalRepo:        goalRepo,
+		sessionRepo:     sessionRepo,
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+func (uc *goalUseCase) CreateGoal(ctx context.Context, userID string, req dto.CreateGoalRequest) (*dto.GoalResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	now := time.Now()
+	goal := &entity.Goal{
+		ID:           primitive.NewObjectID(),
+		UserID:       userObjID,
+		Title:        strings.TrimSpace(req.Title),
+		Metric:       entity.GoalMetric(req.Metric),
+		Period:       entity.GoalPeriod(req.Period),
+		Target:       req.Target,
+		Tag:          strings.TrimSpace(req.Tag),
+		LocationType: strings.TrimSpace(req.LocationType),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Active:       true,
+	}
+
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+
+	if err := uc.goalRepo.Create(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToGoalResponse(goal)
+	return &response, nil
+}
+
+func (uc *goalUseCase) GetGoalByID(ctx context.Context, id string, userID string) (*dto.GoalResponse, error) {
+	goal, err := uc.getOwnedGoal(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToGoalResponse(goal)
+	return &response, nil
+}
+
+func (uc *goalUseCase) GetUserGoals(ctx context.Context, userID string) ([]dto.GoalResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	goals, err := uc.goalRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.GoalResponse, 0, len(goals))
+	for _, goal := range goals {
+		response = append(response, dto.ToGoalResponse(goal))
+	}
+
+	return response, nil
+}
+
+func (uc *goalUseCase) UpdateGoal(ctx context.Context, id string, userID string, req dto.UpdateGoalRequest) (*dto.GoalResponse, error) {
+	goal, err := uc.getOwnedGoal(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != "" {
+		goal.Title = strings.TrimSpace(req.Title)
+	}
+	if req.Metric != "" {
+		goal.Metric = entity.GoalMetric(req.Metric)
+	}
+	if req.Period != "" {
+		goal.Period = entity.GoalPeriod(req.Period)
+	}
+	if req.Target != nil {
+		goal.Target = *req.Target
+	}
+	if req.Tag != nil {
+		goal.Tag = strings.TrimSpace(*req.Tag)
+	}
+	if req.LocationType != nil {
+		goal.LocationType = strings.TrimSpace(*req.LocationType)
+	}
+
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+
+	if err := uc.goalRepo.Update(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToGoalResponse(goal)
+	return &response, nil
+}
+
+func (uc *goalUseCase) DeleteGoal(ctx context.Context, id string, userID string) error {
+	goal, err := uc.getOwnedGoal(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return uc.goalRepo.Delete(ctx, goal.ID)
+}
+
+// GetGoalProgress measures a goal over the period containing the requested
+// day. For the current period, progress is projected from the pace so far.
+func (uc *goalUseCase) GetGoalProgress(ctx context.Context, id string, userID string, req dto.GetGoalProgressRequest) (*dto.GoalProgressResponse, error) {
+	goal, err := uc.getOwnedGoal(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := userLocation(ctx, uc.preferencesRepo, goal.UserID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := goalProgressTime(req.Date, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := uc.measure(ctx, goal.UserID, []*entity.Goal{goal}, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return &progress[0], nil
+}
+
+// GetAllGoalProgress measures every goal of the user
+func (uc *goalUseCase) GetAllGoalProgress(ctx context.Context, userID string, req dto.GetGoalProgressRequest) ([]dto.GoalProgressResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	loc, err := userLocation(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := goalProgressTime(req.Date, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := uc.goalRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.measure(ctx, userObjID, goals, at)
+}
+
+// measure loads the sessions of the widest period once and measures each goal
+func (uc *goalUseCase) measure(ctx context.Context, userID primitive.ObjectID, goals []*entity.Goal, at time.Time) ([]dto.GoalProgressResponse, error) {
+	response := make([]dto.GoalProgressResponse, 0, len(goals))
+	if len(goals) == 0 {
+		return response, nil
+	}
+
+	var from, to time.Time
+	for _, goal := range goals {
+		start, end := goal.Period.Bounds(at)
+		if from.IsZero() || start.Before(from) {
+			from = start
+		}
+		if end.After(to) {
+			to = end
+		}
+	}
+
+	var sessions []*entity.FocusSession
+	err := uc.sessionRepo.ForEachSession(ctx, userID, from, to.Add(-time.Nanosecond), func(session *entity.FocusSession) error {
+		if session.Status == entity.StatusCompleted {
+			sessions = append(sessions, session)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, goal := range goals {
+		response = append(response, dto.ToGoalProgressResponse(goal, goal.Progress(sessions, at)))
+	}
+
+	return response, nil
+}
+
+func (uc *goalUseCase) getOwnedGoal(ctx context.Context, id string, userID string) (*entity.Goal, error) {
+	goalID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidGoalID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	goal, err := uc.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if goal.UserID != userObjID {
+		return nil, ErrNoGoalFoundAccessDenied
+	}
+
+	return goal, nil
+}
+
+func validateGoal(goal *entity.Goal) error {
+	switch {
+	case goal.Title == "":
+		return ErrGoalTitleRequired
+	case !goal.Metric.IsValid():
+		return ErrInvalidGoalMetric
+	case !goal.Period.IsValid():
+		return ErrInvalidGoalPeriod
+	case goal.Target <= 0:
+		return ErrInvalidGoalTarget
+	case goal.Metric == entity.MetricAverageFocus && goal.Target > 10:
+		return ErrInvalidGoalTarget
+	}
+	return nil
+}
+
+// goalProgressTime returns the moment progress is measured at, in loc: now,
+// or the end of the requested day when it is in the past. Future days are
+// refused.
+func goalProgressTime(date string, loc *time.Location) (time.Time, error) {
+	now := time.Now().In(loc)
+	if date == "" {
+		return now, nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return time.Time{}, ErrInvalidDateRange
+	}
+
+	if day.After(now) {
+		return time.Time{}, ErrInvalidDateRange
+	}
+
+	endOfDay := day.AddDate(0, 0, 1).Add(-1 * time.Second)
+	if endOfDay.Before(now) {
+		return endOfDay, nil
+	}
+	return now, nil
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidReviewRating = errors.New("invalid rating (use 1 to 5)")
+	ErrEmptyReview         = errors.New("review needs a rating or a text")
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrInvalidRatingRange  = errors.New("invalid rating range (use e.g. 1-2)")
+)
+
+// Reviews listed per page, and spots listed by insights, when the request
+// gives no limit
+const (
+	defaultReviewLimit  = 20
+	defaultInsightLimit = 10
+)
+
+type ILocationReviewUseCase interface {
+	ReviewLocation(ctx context.Context, locationID string, userID string, req dto.ReviewLocationRequest) (*dto.LocationReviewResponse, error)
+	DeleteReview(ctx context.Context, locationID string, userID string) error
+	GetLocationReviews(ctx context.Context, locationID string, userID string, req dto.GetLocationReviewsRequest) (*dto.LocationReviewsResponse, error)
+	GetSpotInsights(ctx context.Context, userID string, req dto.GetSpotInsightsRequest) (*dto.SpotInsightsResponse, error)
+}
+
+type locationReviewUseCase struct {
+	reviewRepo   interfaces.ILocationReviewRepository
+	locationRepo interfaces.ILocationRepository
+	sessionRepo  interfaces.IFocusSessionRepository
+}
+
+func NewLocationReviewUseCase(
+	reviewRepo interfaces.ILocationReviewRepository,
+	locationRepo interfaces.ILocationRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+) ILocationReviewUseCase {
+	return &locationReviewUseCase{
+		reviewRepo:   reviewRepo,
+		locationRepo: locationRepo,
+		sessionRepo:  sessionRepo,
+	}
+}
+
+// ReviewLocation stores the user's review of a spot, replacing their earlier
+// one
+func (uc *locationReviewUseCase) ReviewLocation(ctx context.Context, locationID string, userID string, req dto.ReviewLocationRequest) (*dto.LocationReviewResponse, error) {
+	locationObjID, err := primitive.ObjectIDFromHex(locationID)
+	if err != nil {
+		return nil, ErrInvalidLocationID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if _, err := uc.locationRepo.GetByID(ctx, locationObjID, userObjID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review := &entity.LocationReview{
+		ID:         primitive.NewObjectID(),
+		LocationID: locationObjID,
+		UserID:     userObjID,
+		Noise:      req.Noise,
+		WiFi:       req.WiFi,
+		Outlets:    req.Outlets,
+		Seating:    req.Seating,
+		Crowding:   req.Crowding,
+		Text:       strings.TrimSpace(req.Text),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	rated := false
+	for _, aspect := range entity.ReviewAspects {
+		rating := review.Rating(aspect)
+		if rating == nil {
+			continue
+		}
+		if *rating < entity.MinReviewRating || *rating > entity.MaxReviewRating {
+			return nil, ErrInvalidReviewRating
+		}
+		rated = true
+	}
+	if !rated && review.Text == "" {
+		return nil, ErrEmptyReview
+	}
+
+	existing, err := uc.reviewRepo.GetByUser(ctx, locationObjID, userObjID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		review.ID = existing.ID
+		review.CreatedAt = existing.CreatedAt
+	}
+
+	if err := uc.reviewRepo.Upsert(ctx, review); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToLocationReviewResponse(review)
+	return &response, nil
+}
+
+func (uc *locationReviewUseCase) DeleteReview(ctx context.Context, locationID string, userID string) error {
+	locationObjID, err := primitive.ObjectIDFromHex(locationID)
+	if err != nil {
+		return ErrInvalidLocationID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	existing, err := uc.reviewRepo.GetByUser(ctx, locationObjID, userObjID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrReviewNotFound
+	}
+
+	return uc.reviewRepo.Delete(ctx, locationObjID, userObjID)
+}
+
+// GetLocationReviews returns a spot's average ratings and a page of its
+// reviews; reviews are shared by all users who may see the spot
+func (uc *locationReviewUseCase) GetLocationReviews(ctx context.Context, locationID string, userID string, req dto.GetLocationReviewsRequest) (*dto.LocationReviewsResponse, error) {
+	locationObjID, err := primitive.ObjectIDFromHex(locationID)
+	if err != nil {
+		return nil, ErrInvalidLocationID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if _, err := uc.locationRepo.GetByID(ctx, locationObjID, userObjID); err != nil {
+		return nil, err
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = defaultReviewLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	ratings, err := uc.reviewRepo.GetRatings(ctx, []primitive.ObjectID{locationObjID})
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := uc.reviewRepo.ListByLocation(ctx, locationObjID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.LocationReviewsResponse{
+		Reviews: make([]dto.LocationReviewResponse, 0, len(reviews)),
+	}
+	if len(ratings) > 0 {
+		response.Ratings = dto.ToLocationRatingsResponse(ratings[0])
+	}
+	for _, review := range reviews {
+		response.Reviews = append(response.Reviews, dto.ToLocationReviewResponse(review))
+	}
+
+	return response, nil
+}
+
+// GetSpotInsights correlates the ratings of the spots the user has been to
+// with the focus and distractions they recorded there, and lists the spots
+// matching the requested ratings, best focus first
+func (uc *locationReviewUseCase) GetSpotInsights(ctx context.Context, userID string, req dto.GetSpotInsightsRequest) (*dto.SpotInsightsResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	ranges := make(map[entity.ReviewAspect]entity.RatingRange)
+	for aspect, raw := range map[entity.ReviewAspect]string{
+		entity.AspectNoise:    req.Noise,
+		entity.AspectWiFi:     req.WiFi,
+		entity.AspectOutlets:  req.Outlets,
+		entity.AspectSeating:  req.Seating,
+		entity.AspectCrowding: req.Crowding,
+	} {
+		if raw == "" {
+			continue
+		}
+		bounds, err := parseRatingRange(raw)
+		if err != nil {
+			return nil, err
+		}
+		ranges[aspect] = bounds
+	}
+
+	locationType := entity.LocationType(req.Type)
+	if locationType != "" && !locationType.IsValid() {
+		return nil, ErrInvalidLocationType
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = defaultInsightLimit
+	}
+
+	var sessions []*entity.FocusSession
+	var locationIDs []primitive.ObjectID
+	seen := make(map[primitive.ObjectID]bool)
+	err = uc.sessionRepo.ForEachSession(ctx, userObjID, time.Time{}, time.Time{}, func(session *entity.FocusSession) error {
+		if session.Status != entity.StatusCompleted || session.LocationID == nil {
+			return nil
+		}
+		if session.Focus == nil && session.Distractions == nil {
+			return nil
+		}
+
+		sessions = append(sessions, session)
+		if !seen[*session.LocationID] {
+			seen[*session.LocationID] = true
+			locationIDs = append(locationIDs, *session.LocationID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ratings, err := uc.reviewRepo.GetRatings(ctx, locationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	correlations, fits := entity.ComputeSpotInsights(sessions, ratings)
+
+	fitIDs := make([]primitive.ObjectID, 0, len(fits))
+	for _, fit := range fits {
+		fitIDs = append(fitIDs, fit.Ratings.LocationID)
+	}
+
+	locations, err := uc.locationRepo.GetByIDs(ctx, fitIDs, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*entity.Location, len(locations))
+	for _, location := range locations {
+		byID[location.ID] = location
+	}
+
+	response := &dto.SpotInsightsResponse{
+		Correlations: make([]dto.RatingCorrelationResponse, 0, len(correlations)),
+		Spots:        []dto.SpotFitResponse{},
+	}
+	for _, correlation := range correlations {
+		response.Correlations = append(response.Correlations, dto.ToRatingCorrelationResponse(correlation))
+	}
+	for _, fit := range fits {
+		if len(response.Spots) == req.Limit {
+			break
+		}
+
+		// Deleted spots still count towards the correlations
+		location, ok := byID[fit.Ratings.LocationID]
+		if !ok {
+			continue
+		}
+		if locationType != "" && location.Type != locationType {
+			continue
+		}
+		if !fit.Ratings.Matches(ranges) {
+			continue
+		}
+
+		response.Spots = append(response.Spots, dto.ToSpotFitResponse(location, fit))
+	}
+
+	return response, nil
+}
+
+// parseRatingRange reads "min-max" or a single rating
+func parseRatingRange(raw string) (entity.RatingRange, error) {
+	low, high, found := strings.Cut(raw, "-")
+	if !found {
+		high = low
+	}
+
+	bounds := entity.RatingRange{}
+	var err error
+	if bounds.Min, err = strconv.ParseFloat(strings.TrimSpace(low), 64); err != nil {
+		return entity.RatingRange{}, ErrInvalidRatingRange
+	}
+	if bounds.Max, err = strconv.ParseFloat(strings.TrimSpace(high), 64); err != nil {
+		return entity.RatingRange{}, ErrInvalidRatingRange
+	}
+
+	if bounds.Min < entity.MinReviewRating || bounds.Max > entity.MaxReviewRating || bounds.Min > bounds.Max {
+		return entity.RatingRange{}, ErrInvalidRatingRange
+	}
+
+	return bounds, nil
+}
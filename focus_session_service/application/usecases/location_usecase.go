@@ -0,0 +1,579 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrLocationNameRequired        = errors.New("location name is required")
+	ErrInvalidLocationType         = errors.New("invalid location type (use cafe, library, coworking, office, home, park or other)")
+	ErrInvalidCoordinates          = errors.New("invalid coordinates")
+	ErrInvalidOpeningHours         = errors.New("invalid opening hours (use a weekday name and HH:MM times)")
+	ErrNoLocationFoundAccessDenied = errors.New("no location found or access denied")
+	ErrInvalidRadius               = errors.New("invalid radius (use up to 50000 meters)")
+	ErrInvalidPlannedTime          = errors.New("invalid planned time (use RFC 3339)")
+)
+
+// Spots listed per page when the request gives no limit
+const defaultLocationLimit = 50
+
+// Nearby spots are searched within this many meters, and listed up to this
+// many by default
+const (
+	defaultNearbyRadius = 2000
+	maxNearbyRadius     = 50000
+	defaultNearbyLimit  = 20
+)
+
+// Recommendations rest on the sessions of the last year, and list this many
+// spots and times by default
+const (
+	recommendationHistoryDays  = 365
+	defaultRecommendationLimit = 5
+)
+
+type ILocationUseCase interface {
+	CreateLocation(ctx context.Context, userID string, req dto.CreateLocationRequest) (*dto.LocationResponse, error)
+	GetLocationByID(ctx context.Context, id string, userID string) (*dto.LocationResponse, error)
+	GetLocations(ctx context.Context, userID string, req dto.GetLocationsRequest) ([]dto.LocationResponse, error)
+	UpdateLocation(ctx context.Context, id string, userID string, req dto.UpdateLocationRequest) (*dto.LocationResponse, error)
+	DeleteLocation(ctx context.Context, id string, userID string) error
+	GetNearbyLocations(ctx context.Context, userID string, req dto.GetNearbyLocationsRequest) (*dto.NearbyLocationsResponse, error)
+	GetRecommendations(ctx context.Context, userID string, req dto.GetRecommendationsRequest) (*dto.RecommendationsResponse, error)
+}
+
+type locationUseCase struct {
+	locationRepo    interfaces.ILocationRepository
+	sessionRepo     interfaces.IFocusSessionRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+}
+
+func NewLocationUseCase(
+	locationRepo interfaces.ILocationRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+) ILocationUseCase {
+	return &locationUseCase{
+		locationRepo:    locationRepo,
+		sessionRepo:     sessionRepo,
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+func (uc *locationUseCase) CreateLocation(ctx context.Context, userID string, req dto.CreateLocationRequest) (*dto.LocationResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if req.Latitude == nil || req.Longitude == nil {
+		return nil, ErrInvalidCoordinates
+	}
+
+	openingHours, err := toOpeningHours(req.OpeningHours)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	location := &entity.Location{
+		ID:           primitive.NewObjectID(),
+		Name:         strings.TrimSpace(req.Name),
+		Address:      strings.TrimSpace(req.Address),
+		Coordinates:  entity.NewGeoPoint(*req.Latitude, *req.Longitude),
+		Type:         entity.LocationType(req.Type),
+		Amenities:    normalizeAmenities(req.Amenities),
+		OpeningHours: openingHours,
+		Timezone:     req.Timezone,
+		Private:      req.Private,
+		CreatedBy:    userObjID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Active:       true,
+	}
+
+	location.ApplyPrivacy()
+
+	if err := validateLocation(location); err != nil {
+		return nil, err
+	}
+
+	if err := uc.locationRepo.Create(ctx, location); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToLocationResponse(location)
+	return &response, nil
+}
+
+// GetLocationByID returns any spot the user may see; spots are shared by all
+// users, except private ones
+func (uc *locationUseCase) GetLocationByID(ctx context.Context, id string, userID string) (*dto.LocationResponse, error) {
+	locationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidLocationID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	location, err := uc.locationRepo.GetByID(ctx, locationID, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToLocationResponse(location)
+	return &response, nil
+}
+
+func (uc *locationUseCase) GetLocations(ctx context.Context, userID string, req dto.GetLocationsRequest) ([]dto.LocationResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	filter := entity.LocationFilter{
+		Viewer:    userObjID,
+		Type:      entity.LocationType(req.Type),
+		Name:      strings.TrimSpace(req.Query),
+		Amenities: normalizeAmenities(req.Amenities),
+	}
+
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, ErrInvalidLocationType
+	}
+
+	if req.Mine {
+		filter.CreatedBy = &userObjID
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = defaultLocationLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	locations, err := uc.locationRepo.List(ctx, filter, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.LocationResponse, 0, len(locations))
+	for _, location := range locations {
+		response = append(response, dto.ToLocationResponse(location))
+	}
+
+	return response, nil
+}
+
+func (uc *locationUseCase) UpdateLocation(ctx context.Context, id string, userID string, req dto.UpdateLocationRequest) (*dto.LocationResponse, error) {
+	location, err := uc.getOwnedLocation(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		location.Name = strings.TrimSpace(req.Name)
+	}
+	if req.Address != nil {
+		location.Address = strings.TrimSpace(*req.Address)
+	}
+	if req.Latitude != nil || req.Longitude != nil {
+		latitude, longitude := location.Coordinates.Latitude(), location.Coordinates.Longitude()
+		if req.Latitude != nil {
+			latitude = *req.Latitude
+		}
+		if req.Longitude != nil {
+			longitude = *req.Longitude
+		}
+		location.Coordinates = entity.NewGeoPoint(latitude, longitude)
+	}
+	if req.Type != "" {
+		location.Type = entity.LocationType(req.Type)
+	}
+	if req.Amenities != nil {
+		location.Amenities = normalizeAmenities(*req.Amenities)
+	}
+	if req.OpeningHours != nil {
+		location.OpeningHours, err = toOpeningHours(*req.OpeningHours)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Timezone != nil {
+		location.Timezone = *req.Timezone
+	}
+	if req.Private != nil {
+		location.Private = *req.Private
+	}
+
+	location.ApplyPrivacy()
+
+	if err := validateLocation(location); err != nil {
+		return nil, err
+	}
+
+	if err := uc.locationRepo.Update(ctx, location); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToLocationResponse(location)
+	return &response, nil
+}
+
+// DeleteLocation removes a spot. Sessions at it keep their location details.
+func (uc *locationUseCase) DeleteLocation(ctx context.Context, id string, userID string) error {
+	location, err := uc.getOwnedLocation(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return uc.locationRepo.Delete(ctx, location.ID)
+}
+
+// GetNearbyLocations returns the spots near a point, nearest first, with the
+// user's productivity at each
+func (uc *locationUseCase) GetNearbyLocations(ctx context.Context, userID string, req dto.GetNearbyLocationsRequest) (*dto.NearbyLocationsResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if req.Latitude == nil || req.Longitude == nil {
+		return nil, ErrInvalidCoordinates
+	}
+
+	point := entity.NewGeoPoint(*req.Latitude, *req.Longitude)
+	if !point.IsValid() {
+		return nil, ErrInvalidCoordinates
+	}
+
+	filter := entity.LocationFilter{Viewer: userObjID, Type: entity.LocationType(req.Type)}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, ErrInvalidLocationType
+	}
+
+	if req.Radius <= 0 {
+		req.Radius = defaultNearbyRadius
+	}
+	if req.Radius > maxNearbyRadius {
+		return nil, ErrInvalidRadius
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultNearbyLimit
+	}
+
+	scorer, err := userScorer(ctx, uc.preferencesRepo, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := uc.locationRepo.GetNearby(ctx, point, req.Radius, filter, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	locationIDs := make([]primitive.ObjectID, 0, len(locations))
+	for _, location := range locations {
+		locationIDs = append(locationIDs, location.ID)
+	}
+
+	productivity, err := uc.sessionRepo.GetLocationProductivity(ctx, userObjID, locationIDs, scorer)
+	if err != nil {
+		return nil, err
+	}
+
+	byLocation := make(map[primitive.ObjectID]*entity.LocationProductivity, len(productivity))
+	for _, p := range productivity {
+		byLocation[p.LocationID] = p
+	}
+
+	response := &dto.NearbyLocationsResponse{
+		ScoreVersion: scorer.Version(),
+		Locations:    make([]dto.NearbyLocationResponse, 0, len(locations)),
+	}
+	for _, location := range locations {
+		response.Locations = append(response.Locations, dto.ToNearbyLocationResponse(location, byLocation[location.ID]))
+	}
+
+	return response, nil
+}
+
+// GetRecommendations ranks the spots the user has been to, and those near the
+// requested point, for a session at the planned time, and lists the times of
+// the week the user focuses best at
+func (uc *locationUseCase) GetRecommendations(ctx context.Context, userID string, req dto.GetRecommendationsRequest) (*dto.RecommendationsResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	loc, scorer, err := userPreferences(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var plannedAt *time.Time
+	if req.At != "" {
+		at, err := time.Parse(time.RFC3339, req.At)
+		if err != nil {
+			return nil, ErrInvalidPlannedTime
+		}
+		at = at.In(loc)
+		plannedAt = &at
+	}
+
+	filter := entity.LocationFilter{Viewer: userObjID, Type: entity.LocationType(req.Type)}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, ErrInvalidLocationType
+	}
+
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, ErrInvalidCoordinates
+	}
+	if req.Radius <= 0 {
+		req.Radius = defaultNearbyRadius
+	}
+	if req.Radius > maxNearbyRadius {
+		return nil, ErrInvalidRadius
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultRecommendationLimit
+	}
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -recommendationHistoryDays)
+
+	var sessions []*entity.FocusSession
+	var visited []primitive.ObjectID
+	seen := make(map[primitive.ObjectID]bool)
+	err = uc.sessionRepo.ForEachSession(ctx, userObjID, start, end, func(session *entity.FocusSession) error {
+		sessions = append(sessions, session)
+		if session.LocationID != nil && !seen[*session.LocationID] {
+			seen[*session.LocationID] = true
+			visited = append(visited, *session.LocationID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := uc.locationRepo.GetByIDs(ctx, visited, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Latitude != nil {
+		point := entity.NewGeoPoint(*req.Latitude, *req.Longitude)
+		if !point.IsValid() {
+			return nil, ErrInvalidCoordinates
+		}
+
+		nearby, err := uc.locationRepo.GetNearby(ctx, point, req.Radius, filter, defaultNearbyLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, location := range nearby {
+			if !seen[location.ID] {
+				seen[location.ID] = true
+				candidates = append(candidates, &location.Location)
+			}
+		}
+	}
+
+	if filter.Type != "" {
+		matching := candidates[:0]
+		for _, location := range candidates {
+			if location.Type == filter.Type {
+				matching = append(matching, location)
+			}
+		}
+		candidates = matching
+	}
+
+	// Order candidates by name so that equal scores rank the same way on
+	// every request
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	var community map[primitive.ObjectID]*entity.LocationProductivity
+	if req.Community {
+		candidateIDs := make([]primitive.ObjectID, 0, len(candidates))
+		for _, location := range candidates {
+			candidateIDs = append(candidateIDs, location.ID)
+		}
+
+		productivity, err := uc.sessionRepo.GetCommunityLocationProductivity(ctx, userObjID, candidateIDs, scorer)
+		if err != nil {
+			return nil, err
+		}
+
+		community = make(map[primitive.ObjectID]*entity.LocationProductivity, len(productivity))
+		for _, p := range productivity {
+			community[p.LocationID] = p
+		}
+	}
+
+	history := entity.NewFocusHistory(sessions, loc, scorer)
+
+	spots := history.RecommendSpots(candidates, plannedAt, community)
+	if len(spots) > req.Limit {
+		spots = spots[:req.Limit]
+	}
+
+	times := history.RecommendTimes(filter.Type)
+	if len(times) > req.Limit {
+		times = times[:req.Limit]
+	}
+
+	response := &dto.RecommendationsResponse{
+		ScoreVersion: scorer.Version(),
+		Timezone:     loc.String(),
+		PlannedAt:    plannedAt,
+		Spots:        make([]dto.SpotRecommendationResponse, 0, len(spots)),
+		Times:        make([]dto.TimeRecommendationResponse, 0, len(times)),
+	}
+	for _, spot := range spots {
+		response.Spots = append(response.Spots, dto.ToSpotRecommendationResponse(spot))
+	}
+	for _, t := range times {
+		response.Times = append(response.Times, dto.ToTimeRecommendationResponse(t))
+	}
+
+	return response, nil
+}
+
+func (uc *locationUseCase) getOwnedLocation(ctx context.Context, id string, userID string) (*entity.Location, error) {
+	locationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidLocationID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	location, err := uc.locationRepo.GetByID(ctx, locationID, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if location.CreatedBy != userObjID {
+		return nil, ErrNoLocationFoundAccessDenied
+	}
+
+	return location, nil
+}
+
+func validateLocation(location *entity.Location) error {
+	switch {
+	case location.Name == "":
+		return ErrLocationNameRequired
+	case !location.Type.IsValid():
+		return ErrInvalidLocationType
+	case !location.Coordinates.IsValid():
+		return ErrInvalidCoordinates
+	}
+
+	if location.Timezone != "" {
+		if _, err := time.LoadLocation(location.Timezone); err != nil {
+			return ErrInvalidTimezone
+		}
+	}
+
+	for _, hours := range location.OpeningHours {
+		if !hours.IsValid() {
+			return ErrInvalidOpeningHours
+		}
+	}
+
+	return nil
+}
+
+// toOpeningHours reads opening hours by weekday name, ordered by day and
+// opening time
+func toOpeningHours(req []dto.OpeningHoursRequest) ([]entity.OpeningHours, error) {
+	openingHours := make([]entity.OpeningHours, 0, len(req))
+	for _, hours := range req {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(hours.Day))]
+		if !ok {
+			return nil, ErrInvalidOpeningHours
+		}
+
+		openingHours = append(openingHours, entity.OpeningHours{
+			Day:   day,
+			Open:  strings.TrimSpace(hours.Open),
+			Close: strings.TrimSpace(hours.Close),
+		})
+	}
+
+	sort.Slice(openingHours, func(i, j int) bool {
+		if openingHours[i].Day != openingHours[j].Day {
+			return openingHours[i].Day < openingHours[j].Day
+		}
+		return openingHours[i].Open < openingHours[j].Open
+	})
+	return openingHours, nil
+}
+
+// normalizeAmenities lowercases amenities and drops blanks and duplicates,
+// keeping them sorted
+func normalizeAmenities(amenities []string) []string {
+	seen := make(map[string]bool, len(amenities))
+	normalized := make([]string, 0, len(amenities))
+	for _, amenity := range amenities {
+		amenity = strings.ToLower(strings.TrimSpace(amenity))
+		if amenity == "" || seen[amenity] {
+			continue
+		}
+		seen[amenity] = true
+		normalized = append(normalized, amenity)
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
+
+// sessionLocation resolves where a user's session or series takes place. A
+// location ID must name an existing spot the user may see, whose details are
+// copied; the given details are then ignored. Without an ID, the given
+// details are kept as they are.
+func sessionLocation(
+	ctx context.Context,
+	locationRepo interfaces.ILocationRepository,
+	userID primitive.ObjectID,
+	locationID string,
+	details *dto.LocationDetailsRequest,
+) (*primitive.ObjectID, *entity.LocationDetails, error) {
+	if locationID == "" {
+		return nil, toLocationDetails(details), nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(locationID)
+	if err != nil {
+		return nil, nil, ErrInvalidLocationID
+	}
+
+	location, err := locationRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &location.ID, location.Details(), nil
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidScorer = errors.New("invalid scorer (use classic, weighted or duration_normalized)")
+
+type IPreferencesUseCase interface {
+	GetPreferences(ctx context.Context, userID string) (*dto.PreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, userID string, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error)
+}
+
+type preferencesUseCase struct {
+	preferencesRepo interfaces.IUserPreferencesRepository
+}
+
+func NewPreferencesUseCase(preferencesRepo interfaces.IUserPreferencesRepository) IPreferencesUseCase {
+	return &preferencesUseCase{
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+func (uc *preferencesUseCase) GetPreferences(ctx context.Context, userID string) (*dto.PreferencesResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	preferences, err := uc.preferencesRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToPreferencesResponse(preferences)
+	return &response, nil
+}
+
+func (uc *preferencesUseCase) UpdatePreferences(ctx context.Context, userID string, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	preferences, err := uc.preferencesRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	if preferences == nil {
+		preferences = &entity.UserPreferences{
+			UserID:    userObjID,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	if req.Timezone != nil {
+		if *req.Timezone != "" {
+			if _, err := time.LoadLocation(*req.Timezone); err != nil {
+				return nil, ErrInvalidTimezone
+			}
+		}
+		preferences.Timezone = *req.Timezone
+	}
+
+	if req.Scorer != nil {
+		if *req.Scorer != "" {
+			if _, ok := entity.ScorerByName(*req.Scorer); !ok {
+				return nil, ErrInvalidScorer
+			}
+		}
+		preferences.Scorer = *req.Scorer
+	}
+
+	if err := uc.preferencesRepo.Save(ctx, preferences); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToPreferencesResponse(preferences)
+	return &response, nil
+}
+
+// userLocation returns the timezone a user's request is read in: the one the
+// request names, else the user's preferred one, else UTC
+func userLocation(ctx context.Context, preferencesRepo interfaces.IUserPreferencesRepository, userID primitive.ObjectID, requested string) (*time.Location, error) {
+	if requested != "" {
+		loc, err := time.LoadLocation(requested)
+		if err != nil {
+			return nil, ErrInvalidTimezone
+		}
+		return loc, nil
+	}
+
+	preferences, err := preferencesRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferredLocation(preferences), nil
+}
+
+// userScorer returns the productivity scorer the user chose, or the default
+func userScorer(ctx context.Context, preferencesRepo interfaces.IUserPreferencesRepository, userID primitive.ObjectID) (entity.ProductivityScorer, error) {
+	preferences, err := preferencesRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferredScorer(preferences), nil
+}
+
+// userPreferences returns both the timezone and the scorer of a user's
+// request, reading the preferences once
+func userPreferences(ctx context.Context, preferencesRepo interfaces.IUserPreferencesRepository, userID primitive.ObjectID, requested string) (*time.Location, entity.ProductivityScorer, error) {
+	preferences, err := preferencesRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loc := preferredLocation(preferences)
+	if requested != "" {
+		loc, err = time.LoadLocation(requested)
+		if err != nil {
+			return nil, nil, ErrInvalidTimezone
+		}
+	}
+
+	return loc, preferredScorer(preferences), nil
+}
+
+func preferredLocation(preferences *entity.UserPreferences) *time.Location {
+	if preferences != nil && preferences.Timezone != "" {
+		if loc, err := time.LoadLocation(preferences.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+func preferredScorer(preferences *entity.UserPreferences) entity.ProductivityScorer {
+	if preferences != nil {
+		if scorer, ok := entity.ScorerByName(preferences.Scorer); ok {
+			return scorer
+		}
+	}
+	return entity.DefaultScorer
+}
+
+// parseDateRange reads an inclusive range of days in loc. Either end may be
+// empty and is then left open (zero).
+func parseDateRange(startDate, endDate string, loc *time.Location) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+
+	if startDate != "" {
+		start, err = time.ParseInLocation("2006-01-02", startDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+	}
+
+	if endDate != "" {
+		end, err = time.ParseInLocation("2006-01-02", endDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+
+		// Make endDate inclusive by setting it to the end of the day
+		end = end.AddDate(0, 0, 1).Add(-1 * time.Second)
+	}
+
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IRollupUseCase interface {
+	// RebuildRollups recomputes all of a user's daily rollups from their
+	// sessions, in their preferred timezone
+	RebuildRollups(ctx context.Context, userID primitive.ObjectID) error
+
+	// Rollups follow session changes, adding and taking away the sessions
+	// that changed
+	interfaces.ISessionObserver
+}
+
+type rollupUseCase struct {
+	rollupRepo      interfaces.IDailyRollupRepository
+	sessionRepo     interfaces.IFocusSessionRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+}
+
+func NewRollupUseCase(
+	rollupRepo interfaces.IDailyRollupRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+) IRollupUseCase {
+	return &rollupUseCase{
+		rollupRepo:      rollupRepo,
+		sessionRepo:     sessionRepo,
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+func (uc *rollupUseCase) RebuildRollups(ctx context.Context, userID primitive.ObjectID) error {
+	loc, err := userLocation(ctx, uc.preferencesRepo, userID, "")
+	if err != nil {
+		return err
+	}
+
+	return uc.rebuild(ctx, userID, loc)
+}
+
+// OnSessionChanged takes the old version of a session out of its day and
+// adds the new one. Rollups that do not exist yet, or are in a timezone the
+// user no longer prefers, are rebuilt instead.
+func (uc *rollupUseCase) OnSessionChanged(ctx context.Context, before, after *entity.FocusSession) {
+	if !entity.IsRolledUp(before) && !entity.IsRolledUp(after) {
+		return
+	}
+
+	var userID primitive.ObjectID
+	if after != nil {
+		userID = after.UserID
+	} else {
+		userID = before.UserID
+	}
+	if err := uc.apply(ctx, userID, before, after); err != nil {
+		log.Printf("Failed to update daily rollups: %v", err)
+	}
+}
+
+// OnSessionsImported rebuilds the user's rollups once when the import added
+// any session to them
+func (uc *rollupUseCase) OnSessionsImported(ctx context.Context, userID primitive.ObjectID, sessions []*entity.FocusSession) {
+	for _, session := range sessions {
+		if !entity.IsRolledUp(session) {
+			continue
+		}
+
+		if err := uc.RebuildRollups(ctx, userID); err != nil {
+			log.Printf("Failed to rebuild daily rollups: %v", err)
+		}
+		return
+	}
+}
+
+func (uc *rollupUseCase) apply(ctx context.Context, userID primitive.ObjectID, before, after *entity.FocusSession) error {
+	loc, err := userLocation(ctx, uc.preferencesRepo, userID, "")
+	if err != nil {
+		return err
+	}
+
+	timezone, err := uc.rollupRepo.GetTimezone(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// The session is already stored, so a rebuild includes the change
+	if timezone != loc.String() {
+		return uc.rebuild(ctx, userID, loc)
+	}
+
+	if rollup := entity.NewSessionRollup(before, loc, -1); rollup != nil {
+		if err := uc.rollupRepo.Add(ctx, rollup); err != nil {
+			return err
+		}
+	}
+
+	if rollup := entity.NewSessionRollup(after, loc, 1); rollup != nil {
+		if err := uc.rollupRepo.Add(ctx, rollup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *rollupUseCase) rebuild(ctx context.Context, userID primitive.ObjectID, loc *time.Location) error {
+	var sessions []*entity.FocusSession
+	err := uc.sessionRepo.ForEachSession(ctx, userID, time.Time{}, time.Time{}, func(session *entity.FocusSession) error {
+		if entity.IsRolledUp(session) {
+			sessions = append(sessions, session)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.rollupRepo.ReplaceUser(ctx, userID, entity.ComputeDailyRollups(sessions, loc))
+}
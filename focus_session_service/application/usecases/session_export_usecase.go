@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Export formats
+const (
+	ExportCSV    = "csv"
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson"
+)
+
+// csvFlushEvery is how many CSV rows are buffered before flushing to the client
+const csvFlushEvery = 100
+
+var ErrInvalidExportFormat = errors.New("invalid export format (use csv, json or ndjson)")
+
+var exportCSVHeader = []string{
+	"id", "title", "description", "status", "mode",
+	"startTime", "endTime", "duration", "actualDuration", "pausedDuration",
+	"locationId", "locationName", "locationAddress", "locationType", "latitude", "longitude",
+	"tags", "notes", "rating", "focus", "energy", "mood", "distractions",
+	"pomodorosCompleted", "productivityScore", "scoreVersion", "createdAt", "updatedAt",
+}
+
+// SessionExport is a validated export request. Write streams the sessions
+// and may run after the handler has returned, so it takes its own context.
+type SessionExport struct {
+	ContentType string
+	FileName    string
+	Write       func(ctx context.Context, w io.Writer) error
+}
+
+type ISessionExportUseCase interface {
+	ExportSessions(ctx context.Context, userID string, req dto.ExportSessionsRequest) (*SessionExport, error)
+}
+
+type sessionExportUseCase struct {
+	sessionRepo     interfaces.IFocusSessionRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+}
+
+func NewSessionExportUseCase(sessionRepo interfaces.IFocusSessionRepository, preferencesRepo interfaces.IUserPreferencesRepository) ISessionExportUseCase {
+	return &sessionExportUseCase{
+		sessionRepo:     sessionRepo,
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+func (uc *sessionExportUseCase) ExportSessions(ctx context.Context, userID string, req dto.ExportSessionsRequest) (*SessionExport, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	loc, scorer, err := userPreferences(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	forEach := func(ctx context.Context, fn func(dto.FocusSessionResponse) error) error {
+		return uc.sessionRepo.ForEachSession(ctx, userObjID, startDate, endDate, func(session *entity.FocusSession) error {
+			return fn(dto.ToScoredFocusSessionResponse(session, scorer))
+		})
+	}
+
+	switch strings.ToLower(req.Format) {
+	case "", ExportCSV:
+		return &SessionExport{
+			ContentType: "text/csv; charset=utf-8",
+			FileName:    "focus-sessions.csv",
+			Write: func(ctx context.Context, w io.Writer) error {
+				return writeSessionsCSV(ctx, w, forEach)
+			},
+		}, nil
+	case ExportJSON:
+		return &SessionExport{
+			ContentType: "application/json",
+			FileName:    "focus-sessions.json",
+			Write: func(ctx context.Context, w io.Writer) error {
+				return writeSessionsJSON(ctx, w, forEach)
+			},
+		}, nil
+	case ExportNDJSON:
+		return &SessionExport{
+			ContentType: "application/x-ndjson",
+			FileName:    "focus-sessions.ndjson",
+			Write: func(ctx context.Context, w io.Writer) error {
+				encoder := json.NewEncoder(w)
+				return forEach(ctx, func(session dto.FocusSessionResponse) error {
+					return encoder.Encode(session)
+				})
+			},
+		}, nil
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+}
+
+type sessionIterator func(ctx context.Context, fn func(dto.FocusSessionResponse) error) error
+
+// writeSessionsJSON writes a JSON array one element at a time
+func writeSessionsJSON(ctx context.Context, w io.Writer, forEach sessionIterator) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err := forEach(ctx, func(session dto.FocusSessionResponse) error {
+		data, err := json.Marshal(session)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+func writeSessionsCSV(ctx context.Context, w io.Writer, forEach sessionIterator) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := forEach(ctx, func(session dto.FocusSessionResponse) error {
+		if err := writer.Write(toExportCSVRow(session)); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%csvFlushEvery == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func toExportCSVRow(session dto.FocusSessionResponse) []string {
+	row := []string{
+		session.ID,
+		session.Title,
+		session.Description,
+		session.Status,
+		session.Mode,
+		session.StartTime.Format(time.RFC3339),
+		formatOptionalTime(session.EndTime),
+		strconv.Itoa(session.Duration),
+		formatOptionalInt(session.ActualDuration),
+		strconv.Itoa(session.PausedDuration),
+		session.LocationID,
+		"", "", "", "", "",
+		strings.Join(session.Tags, ";"),
+		session.Notes,
+		formatOptionalInt(session.Rating),
+		formatOptionalInt(session.Focus),
+		formatOptionalInt(session.Energy),
+		formatOptionalInt(session.Mood),
+		formatOptionalInt(session.Distractions),
+		"",
+		"",
+		session.ScoreVersion,
+		session.CreatedAt.Format(time.RFC3339),
+		session.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if session.LocationDetails != nil {
+		row[11] = session.LocationDetails.Name
+		row[12] = session.LocationDetails.Address
+		row[13] = session.LocationDetails.Type
+		if session.LocationDetails.Latitude != 0 || session.LocationDetails.Longitude != 0 {
+			row[14] = strconv.FormatFloat(session.LocationDetails.Latitude, 'f', -1, 64)
+			row[15] = strconv.FormatFloat(session.LocationDetails.Longitude, 'f', -1, 64)
+		}
+	}
+
+	if session.Pomodoro != nil {
+		row[23] = strconv.Itoa(session.Pomodoro.CompletedPomodoros)
+	}
+
+	if session.ProductivityScore != nil {
+		row[24] = strconv.FormatFloat(*session.ProductivityScore, 'f', 2, 64)
+	}
+
+	return row
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
@@ -0,0 +1,360 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"focusspot/focussessionservice/utils/csvimport"
+	"focusspot/focussessionservice/utils/ical"
+	"io"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxImportEntries bounds how many events or rows one import may contain
+const maxImportEntries = 5000
+
+var (
+	ErrTooManyImportEntries = errors.New("import file has too many entries (max 5000)")
+	ErrMappingRequired      = errors.New("a column mapping is required for generic CSV imports")
+)
+
+type ISessionImportUseCase interface {
+	ImportICS(ctx context.Context, userID string, file io.Reader, dryRun bool) (*dto.ImportReport, error)
+	ImportCSV(ctx context.Context, userID string, file io.Reader, req dto.ImportCSVRequest) (*dto.ImportReport, error)
+}
+
+type sessionImportUseCase struct {
+	sessionRepo     interfaces.IFocusSessionRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+	observers       sessionObservers
+}
+
+func NewSessionImportUseCase(
+	sessionRepo interfaces.IFocusSessionRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+	observers ...interfaces.ISessionObserver,
+) ISessionImportUseCase {
+	return &sessionImportUseCase{
+		sessionRepo:     sessionRepo,
+		preferencesRepo: preferencesRepo,
+		observers:       observers,
+	}
+}
+
+// importCandidate is an entry read from an import file. Session is nil when
+// the entry is invalid, and result.Error says why.
+type importCandidate struct {
+	result  dto.ImportResultResponse
+	session *entity.FocusSession
+}
+
+// ImportICS creates a session for each VEVENT in the file. Events that ended
+// before now become completed sessions and the rest planned ones. Events
+// already imported, or exported from FocusSpot in the first place, are
+// reported as duplicates. Recurring events are reported as invalid.
+func (uc *sessionImportUseCase) ImportICS(ctx context.Context, userID string, file io.Reader, dryRun bool) (*dto.ImportReport, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	events, err := ical.Parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) > maxImportEntries {
+		return nil, ErrTooManyImportEntries
+	}
+
+	now := time.Now()
+	candidates := make([]importCandidate, len(events))
+	for i, event := range events {
+		candidates[i].result = dto.ImportResultResponse{
+			Index: i + 1,
+			UID:   event.UID,
+			Title: event.Summary,
+		}
+
+		if reason := validateImportedEvent(event); reason != "" {
+			candidates[i].result.Error = reason
+			continue
+		}
+
+		candidates[i].session = toImportedSession(userObjID, event, now)
+	}
+
+	return uc.importSessions(ctx, userObjID, candidates, dryRun)
+}
+
+// ImportCSV creates a completed session for each row of a Toggl Track,
+// Clockify or generic CSV export. Rows are tracked time, so they are
+// recorded at their real start and end times whatever the current time.
+func (uc *sessionImportUseCase) ImportCSV(ctx context.Context, userID string, file io.Reader, req dto.ImportCSVRequest) (*dto.ImportReport, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	format := strings.ToLower(req.Format)
+	if format == "" {
+		format = csvimport.FormatGeneric
+	}
+
+	var mapping csvimport.Mapping
+	if format == csvimport.FormatGeneric {
+		if req.Mapping == nil {
+			return nil, ErrMappingRequired
+		}
+		mapping = toCSVMapping(req.Mapping)
+	} else {
+		mapping, err = csvimport.Preset(format)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	loc, err := userLocation(ctx, uc.preferencesRepo, userObjID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := csvimport.Parse(file, mapping, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) > maxImportEntries {
+		return nil, ErrTooManyImportEntries
+	}
+
+	now := time.Now()
+	candidates := make([]importCandidate, len(entries))
+	for i, entry := range entries {
+		candidates[i].result = dto.ImportResultResponse{
+			Index: entry.Row,
+			Title: entry.Title,
+		}
+
+		if reason := validateImportedEntry(entry, now); reason != "" {
+			candidates[i].result.Error = reason
+			continue
+		}
+
+		candidates[i].result.UID = format + ":" + entry.ID
+		candidates[i].session = toCSVImportedSession(userObjID, entry, candidates[i].result.UID, now)
+	}
+
+	return uc.importSessions(ctx, userObjID, candidates, req.DryRun)
+}
+
+// importSessions saves the valid candidates that are not duplicates and
+// reports on every entry. A dry run only previews the sessions.
+func (uc *sessionImportUseCase) importSessions(ctx context.Context, userID primitive.ObjectID, candidates []importCandidate, dryRun bool) (*dto.ImportReport, error) {
+	report := &dto.ImportReport{
+		DryRun:  dryRun,
+		Results: make([]dto.ImportResultResponse, 0, len(candidates)),
+	}
+	seen := make(map[string]bool, len(candidates))
+	var created []*entity.FocusSession
+
+	var scorer entity.ProductivityScorer
+	if dryRun {
+		var err error
+		scorer, err = userScorer(ctx, uc.preferencesRepo, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, candidate := range candidates {
+		result := candidate.result
+
+		if candidate.session == nil {
+			result.Status = dto.ImportInvalid
+			report.Add(result)
+			continue
+		}
+
+		duplicate, err := uc.isDuplicate(ctx, userID, result.UID)
+		if err != nil {
+			return nil, err
+		}
+
+		if duplicate || seen[result.UID] {
+			result.Status = dto.ImportDuplicate
+			report.Add(result)
+			continue
+		}
+		seen[result.UID] = true
+
+		if dryRun {
+			preview := dto.ToScoredFocusSessionResponse(candidate.session, scorer)
+			result.Status = dto.ImportReady
+			result.Session = &preview
+			report.Add(result)
+			continue
+		}
+
+		if err := uc.sessionRepo.Create(ctx, candidate.session); err != nil {
+			result.Status = dto.ImportInvalid
+			result.Error = err.Error()
+			report.Add(result)
+			continue
+		}
+		created = append(created, candidate.session)
+
+		result.Status = dto.ImportCreated
+		result.SessionID = candidate.session.ID.Hex()
+		report.Add(result)
+	}
+
+	// Streaks, rollups and achievements catch up once for the whole import
+	uc.observers.notifyImported(ctx, userID, created)
+
+	return report, nil
+}
+
+// isDuplicate reports whether an entry with this UID was imported before, or
+// is one of the user's own sessions exported to a calendar
+func (uc *sessionImportUseCase) isDuplicate(ctx context.Context, userID primitive.ObjectID, uid string) (bool, error) {
+	if hexID, ok := strings.CutSuffix(uid, CalendarUIDSuffix); ok {
+		if sessionID, err := primitive.ObjectIDFromHex(hexID); err == nil {
+			session, err := uc.sessionRepo.GetByID(ctx, sessionID)
+			if err == nil && session.UserID == userID {
+				return true, nil
+			}
+		}
+	}
+
+	existing, err := uc.sessionRepo.GetByExternalUID(ctx, userID, uid)
+	if err != nil {
+		return false, err
+	}
+
+	return existing != nil, nil
+}
+
+// validateImportedEvent returns why an event cannot become a session, or ""
+func validateImportedEvent(event ical.ParsedEvent) string {
+	switch {
+	case event.Err != nil:
+		return event.Err.Error()
+	case event.UID == "":
+		return "UID is required"
+	case event.AllDay:
+		return "all-day events are not supported"
+	case event.Status == ical.StatusCancelled:
+		return "event is cancelled"
+	case !event.End.After(event.Start):
+		return "event must end after it starts"
+	}
+	return ""
+}
+
+// validateImportedEntry returns why a CSV row cannot become a session, or ""
+func validateImportedEntry(entry csvimport.Entry, now time.Time) string {
+	switch {
+	case entry.Err != nil:
+		return entry.Err.Error()
+	case !entry.End.After(entry.Start):
+		return "entry must end after it starts"
+	case entry.End.After(now):
+		return "entry ends in the future"
+	}
+	return ""
+}
+
+func toImportedSession(userID primitive.ObjectID, event ical.ParsedEvent, now time.Time) *entity.FocusSession {
+	title := strings.TrimSpace(event.Summary)
+	if title == "" {
+		title = "Imported session"
+	}
+
+	session := &entity.FocusSession{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Title:       title,
+		Description: event.Description,
+		StartTime:   event.Start,
+		Duration:    int(event.End.Sub(event.Start).Minutes()),
+		Status:      entity.StatusPlanned,
+		Tags:        event.Categories,
+		ExternalUID: event.UID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Active:      true,
+	}
+
+	if event.Location != "" || event.Geo != nil {
+		session.LocationDetails = &entity.LocationDetails{
+			Name: event.Location,
+		}
+		if event.Geo != nil {
+			session.LocationDetails.Latitude = event.Geo.Latitude
+			session.LocationDetails.Longitude = event.Geo.Longitude
+		}
+	}
+
+	// Past events are history: record them as completed at their real times
+	if event.End.Before(now) {
+		session.CompleteAt(event.End)
+	}
+
+	return session
+}
+
+func toCSVImportedSession(userID primitive.ObjectID, entry csvimport.Entry, uid string, now time.Time) *entity.FocusSession {
+	title := entry.Title
+	if title == "" {
+		title = "Imported session"
+	}
+
+	session := &entity.FocusSession{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Title:       title,
+		Description: entry.Description,
+		StartTime:   entry.Start,
+		Duration:    int(entry.End.Sub(entry.Start).Minutes()),
+		Tags:        entry.Tags,
+		Notes:       entry.Notes,
+		ExternalUID: uid,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Active:      true,
+	}
+
+	if entry.Location != "" {
+		session.LocationDetails = &entity.LocationDetails{
+			Name: entry.Location,
+		}
+	}
+
+	session.CompleteAt(entry.End)
+
+	return session
+}
+
+func toCSVMapping(req *dto.CSVMappingRequest) csvimport.Mapping {
+	return csvimport.Mapping{
+		ID:           req.ID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Notes:        req.Notes,
+		Tags:         req.Tags,
+		Location:     req.Location,
+		Start:        req.Start,
+		StartTime:    req.StartTime,
+		End:          req.End,
+		EndTime:      req.EndTime,
+		Duration:     req.Duration,
+		Layout:       req.Layout,
+		TagSeparator: req.TagSeparator,
+	}
+}
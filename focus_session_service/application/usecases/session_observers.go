@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sessionObservers are kept up to date by the use cases that change sessions
+type sessionObservers []interfaces.ISessionObserver
+
+func (o sessionObservers) notify(ctx context.Context, before, after *entity.FocusSession) {
+	for _, observer := range o {
+		observer.OnSessionChanged(ctx, before, after)
+	}
+}
+
+func (o sessionObservers) notifyImported(ctx context.Context, userID primitive.ObjectID, sessions []*entity.FocusSession) {
+	if len(sessions) == 0 {
+		return
+	}
+
+	for _, observer := range o {
+		observer.OnSessionsImported(ctx, userID, sessions)
+	}
+}
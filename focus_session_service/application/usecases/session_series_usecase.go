@@ -0,0 +1,687 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"focusspot/focussessionservice/utils/rrule"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultOccurrenceWindow = 30 * 24 * time.Hour
+	maxOccurrenceWindow     = 366 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidSeriesID        = errors.New("invalid series ID")
+	ErrInvalidTimezone        = errors.New("invalid timezone")
+	ErrInvalidScope           = errors.New("invalid scope (use this, following or all)")
+	ErrOccurrenceRequired     = errors.New("occurrence time is required for this scope")
+	ErrOccurrenceNotInSeries  = errors.New("occurrence is not part of the series")
+	ErrOccurrenceWindowTooBig = errors.New("occurrence window cannot be longer than 366 days")
+	ErrRRuleChangeNeedsScope  = errors.New("the recurrence rule can only be changed for following occurrences or the whole series")
+)
+
+type ISessionSeriesUseCase interface {
+	CreateSeries(ctx context.Context, userID string, req dto.CreateSeriesRequest) (*dto.SeriesResponse, error)
+	GetSeriesByID(ctx context.Context, id string, userID string) (*dto.SeriesResponse, error)
+	GetUserSeries(ctx context.Context, userID string) ([]dto.SeriesResponse, error)
+	GetOccurrences(ctx context.Context, id string, userID string, req dto.GetOccurrencesRequest) (*dto.OccurrencesResponse, error)
+	UpdateSeries(ctx context.Context, id string, userID string, req dto.UpdateSeriesRequest) (*dto.SeriesChangeResponse, error)
+	CancelSeries(ctx context.Context, id string, userID string, req dto.CancelSeriesRequest) (*dto.SeriesChangeResponse, error)
+}
+
+type sessionSeriesUseCase struct {
+	seriesRepo      interfaces.ISessionSeriesRepository
+	sessionRepo     interfaces.IFocusSessionRepository
+	locationRepo    interfaces.ILocationRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+	observers       sessionObservers
+}
+
+func NewSessionSeriesUseCase(
+	seriesRepo interfaces.ISessionSeriesRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+	locationRepo interfaces.ILocationRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+	observers ...interfaces.ISessionObserver,
+) ISessionSeriesUseCase {
+	return &sessionSeriesUseCase{
+		seriesRepo:      seriesRepo,
+		sessionRepo:     sessionRepo,
+		locationRepo:    locationRepo,
+		preferencesRepo: preferencesRepo,
+		observers:       observers,
+	}
+}
+
+func (uc *sessionSeriesUseCase) CreateSeries(ctx context.Context, userID string, req dto.CreateSeriesRequest) (*dto.SeriesResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	if req.Duration <= 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	rule, err := rrule.Parse(req.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	now := time.Now()
+	series := &entity.SessionSeries{
+		ID:          primitive.NewObjectID(),
+		UserID:      userObjID,
+		Title:       req.Title,
+		Description: req.Description,
+		RRule:       rule.String(),
+		StartTime:   req.StartTime.In(loc),
+		Timezone:    req.Timezone,
+		Duration:    req.Duration,
+		Tags:        req.Tags,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Active:      true,
+	}
+
+	series.LocationID, series.LocationDetails, err = sessionLocation(ctx, uc.locationRepo, userObjID, req.LocationID, req.LocationDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.seriesRepo.Create(ctx, series); err != nil {
+		return nil, err
+	}
+
+	response := dto.ToSeriesResponse(series)
+	return &response, nil
+}
+
+func (uc *sessionSeriesUseCase) GetSeriesByID(ctx context.Context, id string, userID string) (*dto.SeriesResponse, error) {
+	series, err := uc.getOwnedSeries(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToSeriesResponse(series)
+	return &response, nil
+}
+
+func (uc *sessionSeriesUseCase) GetUserSeries(ctx context.Context, userID string) ([]dto.SeriesResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	seriesList, err := uc.seriesRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SeriesResponse, 0, len(seriesList))
+	for _, series := range seriesList {
+		response = append(response, dto.ToSeriesResponse(series))
+	}
+
+	return response, nil
+}
+
+// GetOccurrences expands the series within the requested window and returns
+// its occurrences as planned sessions. Occurrences are materialized lazily:
+// the ones not stored yet are created here, on read, since a series has no
+// end to store them all up front. Creating them is idempotent, so repeated and
+// concurrent reads store each occurrence once.
+func (uc *sessionSeriesUseCase) GetOccurrences(
+	ctx context.Context,
+	id string,
+	userID string,
+	req dto.GetOccurrencesRequest,
+) (*dto.OccurrencesResponse, error) {
+	series, err := uc.getOwnedSeries(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := series.Location()
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	// Default to the next 30 days in the series' timezone
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if req.From != "" {
+		from, err = time.ParseInLocation("2006-01-02", req.From, loc)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+	}
+
+	to := from.Add(defaultOccurrenceWindow)
+	if req.To != "" {
+		to, err = time.ParseInLocation("2006-01-02", req.To, loc)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+
+		// Make the end date inclusive
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !to.After(from) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if to.Sub(from) > maxOccurrenceWindow {
+		return nil, ErrOccurrenceWindowTooBig
+	}
+
+	sessions, err := uc.materialize(ctx, series, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	scorer, err := userScorer(ctx, uc.preferencesRepo, series.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionResponses := make([]dto.FocusSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		sessionResponses = append(sessionResponses, dto.ToScoredFocusSessionResponse(session, scorer))
+	}
+
+	return &dto.OccurrencesResponse{
+		Series:   dto.ToSeriesResponse(series),
+		Sessions: sessionResponses,
+		From:     from,
+		To:       to,
+	}, nil
+}
+
+func (uc *sessionSeriesUseCase) UpdateSeries(
+	ctx context.Context,
+	id string,
+	userID string,
+	req dto.UpdateSeriesRequest,
+) (*dto.SeriesChangeResponse, error) {
+	series, err := uc.getOwnedSeries(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := uc.resolveScope(series, req.Scope, req.OccurrenceTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RRule != "" {
+		if scope == entity.ScopeThis {
+			return nil, ErrRRuleChangeNeedsScope
+		}
+
+		rule, err := rrule.Parse(req.RRule)
+		if err != nil {
+			return nil, err
+		}
+		req.RRule = rule.String()
+	}
+
+	// Details are only set when the location changes
+	locationID, locationDetails, err := sessionLocation(ctx, uc.locationRepo, series.UserID, req.LocationID, req.LocationDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scope {
+	case entity.ScopeThis:
+		occurrenceTime := *req.OccurrenceTime
+		occurrence, err := uc.materializeOne(ctx, series, occurrenceTime)
+		if err != nil {
+			return nil, err
+		}
+		before := *occurrence
+
+		if req.Title != "" {
+			occurrence.Title = req.Title
+		}
+		if req.Description != "" {
+			occurrence.Description = req.Description
+		}
+		if req.StartTime != nil {
+			occurrence.StartTime = *req.StartTime
+		}
+		if req.Duration != nil {
+			occurrence.Duration = *req.Duration
+		}
+		if locationDetails != nil {
+			occurrence.LocationID = locationID
+			occurrence.LocationDetails = locationDetails
+		}
+		if req.Tags != nil {
+			occurrence.Tags = req.Tags
+		}
+
+		// Later edits to the whole series leave this occurrence alone
+		occurrence.SeriesOverride = true
+
+		if err := uc.sessionRepo.Update(ctx, occurrence); err != nil {
+			return nil, err
+		}
+
+		// The occurrence may already be completed
+		uc.observers.notify(ctx, &before, occurrence)
+
+		scorer, err := userScorer(ctx, uc.preferencesRepo, series.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		occurrenceResponse := dto.ToScoredFocusSessionResponse(occurrence, scorer)
+		return &dto.SeriesChangeResponse{
+			Series:     dto.ToSeriesResponse(series),
+			Occurrence: &occurrenceResponse,
+		}, nil
+
+	case entity.ScopeFollowing:
+		occurrenceTime := *req.OccurrenceTime
+
+		loc, err := series.Location()
+		if err != nil {
+			return nil, ErrInvalidTimezone
+		}
+
+		// Split the series: the original ends before the occurrence and a
+		// new series with the changes takes over from it
+		following := *series
+		following.ID = primitive.NewObjectID()
+		following.StartTime = occurrenceTime.In(loc)
+		following.CreatedAt = time.Now()
+		following.ExceptionDates = nil
+		for _, exception := range series.ExceptionDates {
+			if !exception.Before(occurrenceTime) {
+				following.ExceptionDates = append(following.ExceptionDates, exception)
+			}
+		}
+
+		if req.RRule == "" {
+			rule, err := rrule.Parse(series.RRule)
+			if err != nil {
+				return nil, err
+			}
+
+			// COUNT covers the whole series, so the new one gets what is left
+			if rule.Count > 0 {
+				rule.Count -= rule.CountBefore(series.StartTime.In(loc), occurrenceTime)
+				following.RRule = rule.String()
+			}
+		}
+
+		if err := applySeriesUpdate(&following, req, locationID, locationDetails); err != nil {
+			return nil, err
+		}
+
+		until := occurrenceTime.Add(-time.Second)
+		series.Until = &until
+		var exceptions []time.Time
+		for _, exception := range series.ExceptionDates {
+			if exception.Before(occurrenceTime) {
+				exceptions = append(exceptions, exception)
+			}
+		}
+		series.ExceptionDates = exceptions
+
+		if err := uc.seriesRepo.Update(ctx, series); err != nil {
+			return nil, err
+		}
+
+		if err := uc.seriesRepo.Create(ctx, &following); err != nil {
+			return nil, err
+		}
+
+		// Occurrences from the split on belong to the new series, so edited,
+		// started and deleted ones are not materialized again beside it
+		if err := uc.sessionRepo.MoveOccurrences(ctx, series.ID, following.ID, occurrenceTime); err != nil {
+			return nil, err
+		}
+
+		if err := uc.refreshOccurrences(ctx, &following, occurrenceTime); err != nil {
+			return nil, err
+		}
+
+		return &dto.SeriesChangeResponse{
+			Series: dto.ToSeriesResponse(&following),
+		}, nil
+
+	default:
+		if err := applySeriesUpdate(series, req, locationID, locationDetails); err != nil {
+			return nil, err
+		}
+
+		if err := uc.seriesRepo.Update(ctx, series); err != nil {
+			return nil, err
+		}
+
+		// Upcoming occurrences follow the new template
+		if err := uc.refreshOccurrences(ctx, series, time.Now()); err != nil {
+			return nil, err
+		}
+
+		return &dto.SeriesChangeResponse{
+			Series: dto.ToSeriesResponse(series),
+		}, nil
+	}
+}
+
+func (uc *sessionSeriesUseCase) CancelSeries(
+	ctx context.Context,
+	id string,
+	userID string,
+	req dto.CancelSeriesRequest,
+) (*dto.SeriesChangeResponse, error) {
+	series, err := uc.getOwnedSeries(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := uc.resolveScope(series, req.Scope, req.OccurrenceTime)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scope {
+	case entity.ScopeThis:
+		occurrenceTime := *req.OccurrenceTime
+		if !series.IsException(occurrenceTime) {
+			series.ExceptionDates = append(series.ExceptionDates, occurrenceTime)
+		}
+
+		if err := uc.seriesRepo.Update(ctx, series); err != nil {
+			return nil, err
+		}
+
+		occurrences, err := uc.sessionRepo.GetBySeriesID(ctx, series.ID, occurrenceTime, occurrenceTime.Add(time.Second))
+		if err != nil {
+			return nil, err
+		}
+
+		scorer, err := userScorer(ctx, uc.preferencesRepo, series.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		response := &dto.SeriesChangeResponse{
+			Series: dto.ToSeriesResponse(series),
+		}
+
+		for _, occurrence := range occurrences {
+			if occurrence.Status == entity.StatusPlanned {
+				if err := uc.sessionRepo.UpdateStatus(ctx, occurrence.ID, entity.StatusCancelled); err != nil {
+					return nil, err
+				}
+				occurrence.Status = entity.StatusCancelled
+			}
+
+			occurrenceResponse := dto.ToScoredFocusSessionResponse(occurrence, scorer)
+			response.Occurrence = &occurrenceResponse
+		}
+
+		return response, nil
+
+	case entity.ScopeFollowing:
+		occurrenceTime := *req.OccurrenceTime
+		until := occurrenceTime.Add(-time.Second)
+		series.Until = &until
+
+		if err := uc.seriesRepo.Update(ctx, series); err != nil {
+			return nil, err
+		}
+
+		if err := uc.sessionRepo.CancelPlannedOccurrences(ctx, series.ID, occurrenceTime); err != nil {
+			return nil, err
+		}
+
+		return &dto.SeriesChangeResponse{
+			Series: dto.ToSeriesResponse(series),
+		}, nil
+
+	default:
+		if err := uc.sessionRepo.CancelPlannedOccurrences(ctx, series.ID, time.Time{}); err != nil {
+			return nil, err
+		}
+
+		if err := uc.seriesRepo.Delete(ctx, series.ID); err != nil {
+			return nil, err
+		}
+
+		series.Active = false
+		return &dto.SeriesChangeResponse{
+			Series: dto.ToSeriesResponse(series),
+		}, nil
+	}
+}
+
+func (uc *sessionSeriesUseCase) getOwnedSeries(ctx context.Context, id string, userID string) (*entity.SessionSeries, error) {
+	seriesID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidSeriesID
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	series, err := uc.seriesRepo.GetByID(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if series.UserID != userObjID {
+		return nil, errors.New("no series found or access denied")
+	}
+
+	return series, nil
+}
+
+// resolveScope validates the scope and occurrence of an edit or cancel.
+// Changing the first occurrence "and following" is the same as changing the
+// whole series.
+func (uc *sessionSeriesUseCase) resolveScope(series *entity.SessionSeries, value string, occurrenceTime *time.Time) (entity.SeriesScope, error) {
+	scope := entity.SeriesScope(value)
+	switch scope {
+	case entity.ScopeAll:
+		return scope, nil
+	case entity.ScopeThis, entity.ScopeFollowing:
+	default:
+		return "", ErrInvalidScope
+	}
+
+	if occurrenceTime == nil {
+		return "", ErrOccurrenceRequired
+	}
+
+	exists, err := series.HasOccurrence(*occurrenceTime)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", ErrOccurrenceNotInSeries
+	}
+
+	if scope == entity.ScopeFollowing && occurrenceTime.Equal(series.StartTime) {
+		return entity.ScopeAll, nil
+	}
+
+	return scope, nil
+}
+
+// materialize stores a planned session for every occurrence in [from, to)
+// and returns the series' sessions in that window. The unique
+// (seriesId, occurrenceTime) index keeps it to one session per occurrence.
+func (uc *sessionSeriesUseCase) materialize(ctx context.Context, series *entity.SessionSeries, from, to time.Time) ([]*entity.FocusSession, error) {
+	occurrences, err := series.Occurrences(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.sessionRepo.GetBySeriesID(ctx, series.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[int64]bool, len(existing))
+	for _, session := range existing {
+		stored[session.OccurrenceTime.Unix()] = true
+	}
+
+	created := false
+	for _, occurrence := range occurrences {
+		if stored[occurrence.Unix()] {
+			continue
+		}
+
+		if err := uc.sessionRepo.UpsertOccurrence(ctx, series.NewOccurrence(occurrence)); err != nil {
+			return nil, err
+		}
+		created = true
+	}
+
+	if created {
+		existing, err = uc.sessionRepo.GetBySeriesID(ctx, series.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Occurrences the user deleted stay stored so they are not recreated
+	sessions := make([]*entity.FocusSession, 0, len(existing))
+	for _, session := range existing {
+		if session.Active {
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartTime.Before(sessions[j].StartTime)
+	})
+
+	return sessions, nil
+}
+
+// materializeOne returns the stored session for a single occurrence
+func (uc *sessionSeriesUseCase) materializeOne(ctx context.Context, series *entity.SessionSeries, occurrenceTime time.Time) (*entity.FocusSession, error) {
+	sessions, err := uc.materialize(ctx, series, occurrenceTime, occurrenceTime.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sessions) == 0 {
+		return nil, ErrNoSessionFound
+	}
+
+	return sessions[0], nil
+}
+
+// refreshOccurrences brings the stored planned occurrences from `from` onward
+// in line with the series template. Occurrences the series no longer has are
+// deleted like any session. Ones the user edited on their own, started or
+// deleted are left alone, so deleted occurrences are not materialized again.
+func (uc *sessionSeriesUseCase) refreshOccurrences(ctx context.Context, series *entity.SessionSeries, from time.Time) error {
+	sessions, err := uc.sessionRepo.GetBySeriesID(ctx, series.ID, from, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if !session.Active || session.Status != entity.StatusPlanned || session.SeriesOverride {
+			continue
+		}
+
+		exists, err := series.HasOccurrence(*session.OccurrenceTime)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := uc.sessionRepo.Delete(ctx, session.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		refreshed := series.NewOccurrence(*session.OccurrenceTime)
+		refreshed.ID = session.ID
+		refreshed.CreatedAt = session.CreatedAt
+		if err := uc.sessionRepo.Update(ctx, refreshed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applySeriesUpdate applies the provided fields of an update to a series
+// template. Location details are set, with their location ID, when the
+// location changes.
+func applySeriesUpdate(series *entity.SessionSeries, req dto.UpdateSeriesRequest, locationID *primitive.ObjectID, locationDetails *entity.LocationDetails) error {
+	if req.Title != "" {
+		series.Title = req.Title
+	}
+	if req.Description != "" {
+		series.Description = req.Description
+	}
+	if req.RRule != "" {
+		series.RRule = req.RRule
+	}
+	if req.StartTime != nil {
+		loc, err := series.Location()
+		if err != nil {
+			return ErrInvalidTimezone
+		}
+		series.StartTime = req.StartTime.In(loc)
+	}
+	if req.Duration != nil {
+		series.Duration = *req.Duration
+	}
+	if locationDetails != nil {
+		series.LocationID = locationID
+		series.LocationDetails = locationDetails
+	}
+	if req.Tags != nil {
+		series.Tags = req.Tags
+	}
+
+	return nil
+}
+
+func toLocationDetails(req *dto.LocationDetailsRequest) *entity.LocationDetails {
+	if req == nil {
+		return nil
+	}
+
+	return &entity.LocationDetails{
+		Name:      req.Name,
+		Address:   req.Address,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Type:      req.Type,
+	}
+}
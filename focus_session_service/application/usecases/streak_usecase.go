@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidStreakThreshold = errors.New("invalid streak threshold")
+	ErrInvalidRestDay         = errors.New("invalid rest day (use weekday names such as saturday)")
+	ErrTooManyRestDays        = errors.New("at least one day of the week must not be a rest day")
+)
+
+type IStreakUseCase interface {
+	GetStreaks(ctx context.Context, userID string) (*dto.StreakResponse, error)
+	UpdateStreakSettings(ctx context.Context, userID string, req dto.UpdateStreakSettingsRequest) (*dto.StreakResponse, error)
+
+	// Streaks follow session changes, so that completing a session does not
+	// need a rescan of the user's history
+	interfaces.ISessionObserver
+}
+
+type streakUseCase struct {
+	streakRepo      interfaces.IStreakRepository
+	sessionRepo     interfaces.IFocusSessionRepository
+	preferencesRepo interfaces.IUserPreferencesRepository
+}
+
+func NewStreakUseCase(
+	streakRepo interfaces.IStreakRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+	preferencesRepo interfaces.IUserPreferencesRepository,
+) IStreakUseCase {
+	return &streakUseCase{
+		streakRepo:      streakRepo,
+		sessionRepo:     sessionRepo,
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+func (uc *streakUseCase) GetStreaks(ctx context.Context, userID string) (*dto.StreakResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	streak, err := uc.streakRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	if streak == nil {
+		streak = &entity.Streak{
+			UserID:   userObjID,
+			Settings: entity.DefaultStreakSettings(),
+			Stale:    true,
+		}
+	}
+
+	return uc.respond(ctx, streak)
+}
+
+// UpdateStreakSettings changes which days qualify, and so recomputes the
+// streaks from the whole history
+func (uc *streakUseCase) UpdateStreakSettings(ctx context.Context, userID string, req dto.UpdateStreakSettingsRequest) (*dto.StreakResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	streak, err := uc.streakRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	if streak == nil {
+		streak = &entity.Streak{
+			UserID:   userObjID,
+			Settings: entity.DefaultStreakSettings(),
+		}
+	}
+
+	if req.ThresholdMinutes != nil {
+		if *req.ThresholdMinutes <= 0 || *req.ThresholdMinutes > 24*60 {
+			return nil, ErrInvalidStreakThreshold
+		}
+		streak.Settings.ThresholdMinutes = *req.ThresholdMinutes
+	}
+
+	if req.RestDays != nil {
+		restDays, err := parseRestDays(req.RestDays)
+		if err != nil {
+			return nil, err
+		}
+		streak.Settings.RestDays = restDays
+	}
+
+	if req.Timezone != nil {
+		if *req.Timezone != "" {
+			if _, err := time.LoadLocation(*req.Timezone); err != nil {
+				return nil, ErrInvalidTimezone
+			}
+		}
+		streak.Settings.Timezone = *req.Timezone
+	}
+
+	streak.Stale = true
+	return uc.respond(ctx, streak)
+}
+
+// OnSessionChanged extends the streak when a session is completed. Any other
+// change to a completed session may change past days, so the streak is
+// marked stale and recomputed the next time it is read.
+func (uc *streakUseCase) OnSessionChanged(ctx context.Context, before, after *entity.FocusSession) {
+	wasCompleted := before != nil && before.Status == entity.StatusCompleted
+	isCompleted := after != nil && after.Status == entity.StatusCompleted
+
+	var err error
+	switch {
+	case wasCompleted && isCompleted && sameFocusTime(before, after):
+		return
+	case wasCompleted:
+		err = uc.streakRepo.MarkStale(ctx, before.UserID)
+	case isCompleted:
+		err = uc.addSession(ctx, after)
+	}
+
+	if err != nil {
+		log.Printf("Failed to update streak: %v", err)
+	}
+}
+
+// OnSessionsImported marks the streak stale when the import completed any
+// session, so it is recomputed once the next time it is read
+func (uc *streakUseCase) OnSessionsImported(ctx context.Context, userID primitive.ObjectID, sessions []*entity.FocusSession) {
+	for _, session := range sessions {
+		if session.Status != entity.StatusCompleted {
+			continue
+		}
+
+		if err := uc.streakRepo.MarkStale(ctx, userID); err != nil {
+			log.Printf("Failed to update streak: %v", err)
+		}
+		return
+	}
+}
+
+// addSession adds the day of a newly completed session if it now reaches the
+// threshold. Only days after the last qualified day are added incrementally.
+func (uc *streakUseCase) addSession(ctx context.Context, session *entity.FocusSession) error {
+	streak, err := uc.streakRepo.GetByUserID(ctx, session.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Streaks are computed in full the first time they are read
+	if streak == nil || streak.Stale {
+		return nil
+	}
+
+	loc, err := uc.location(ctx, streak)
+	if err != nil {
+		return err
+	}
+	if streak.Stale {
+		return uc.streakRepo.MarkStale(ctx, session.UserID)
+	}
+
+	day := entity.StreakDay(session.StartTime, loc)
+	switch {
+	case day == streak.LastQualified:
+		return nil
+	case day < streak.LastQualified:
+		return uc.streakRepo.MarkStale(ctx, session.UserID)
+	}
+
+	minutes, err := uc.dayMinutes(ctx, streak.UserID, loc, day)
+	if err != nil {
+		return err
+	}
+
+	if minutes < streak.Settings.ThresholdMinutes {
+		return nil
+	}
+
+	streak.AddQualifiedDay(day)
+	return uc.streakRepo.Save(ctx, streak)
+}
+
+// respond recomputes a stale streak and saves it, then describes it as of
+// today
+func (uc *streakUseCase) respond(ctx context.Context, streak *entity.Streak) (*dto.StreakResponse, error) {
+	loc, err := uc.location(ctx, streak)
+	if err != nil {
+		return nil, err
+	}
+
+	if streak.Stale {
+		if err := uc.recompute(ctx, streak, loc); err != nil {
+			return nil, err
+		}
+		if err := uc.streakRepo.Save(ctx, streak); err != nil {
+			return nil, err
+		}
+	}
+
+	today := entity.StreakDay(time.Now(), loc)
+	minutes, err := uc.dayMinutes(ctx, streak.UserID, loc, today)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToStreakResponse(streak, today, minutes)
+	return &response, nil
+}
+
+// location returns the timezone the streak's days are in: its own setting,
+// else the user's preference. A streak computed in another zone is marked
+// stale.
+func (uc *streakUseCase) location(ctx context.Context, streak *entity.Streak) (*time.Location, error) {
+	loc, err := userLocation(ctx, uc.preferencesRepo, streak.UserID, streak.Settings.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if streak.Timezone != loc.String() {
+		streak.Timezone = loc.String()
+		streak.Stale = true
+	}
+	return loc, nil
+}
+
+// recompute rebuilds the streak from every completed session of the user
+func (uc *streakUseCase) recompute(ctx context.Context, streak *entity.Streak, loc *time.Location) error {
+	daily := make(map[string]int)
+	err := uc.sessionRepo.ForEachSession(ctx, streak.UserID, time.Time{}, time.Time{}, func(session *entity.FocusSession) error {
+		if session.Status == entity.StatusCompleted && session.ActualDuration != nil {
+			daily[entity.StreakDay(session.StartTime, loc)] += *session.ActualDuration
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	streak.Recompute(daily)
+	return nil
+}
+
+// dayMinutes adds up the completed sessions that started on a day
+func (uc *streakUseCase) dayMinutes(ctx context.Context, userID primitive.ObjectID, loc *time.Location, day string) (int, error) {
+	start, err := time.ParseInLocation(entity.StreakDateLayout, day, loc)
+	if err != nil {
+		return 0, err
+	}
+	end := start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	minutes := 0
+	err = uc.sessionRepo.ForEachSession(ctx, userID, start, end, func(session *entity.FocusSession) error {
+		if session.Status == entity.StatusCompleted && session.ActualDuration != nil {
+			minutes += *session.ActualDuration
+		}
+		return nil
+	})
+
+	return minutes, err
+}
+
+// sameFocusTime reports whether two versions of a session count the same
+// minutes on the same day
+func sameFocusTime(a, b *entity.FocusSession) bool {
+	if !a.StartTime.Equal(b.StartTime) {
+		return false
+	}
+	if a.ActualDuration == nil || b.ActualDuration == nil {
+		return a.ActualDuration == b.ActualDuration
+	}
+	return *a.ActualDuration == *b.ActualDuration
+}
+
+func parseRestDays(names []string) ([]time.Weekday, error) {
+	seen := make(map[time.Weekday]bool, len(names))
+	var restDays []time.Weekday
+
+	for _, name := range names {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, ErrInvalidRestDay
+		}
+		if !seen[day] {
+			seen[day] = true
+			restDays = append(restDays, day)
+		}
+	}
+
+	if len(restDays) == 7 {
+		return nil, ErrTooManyRestDays
+	}
+
+	sort.Slice(restDays, func(i, j int) bool {
+		return restDays[i] < restDays[j]
+	})
+	return restDays, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
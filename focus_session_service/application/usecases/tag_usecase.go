@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidTagName  = errors.New("tag name is required")
+	ErrInvalidTagColor = errors.New("invalid tag color (use #RGB or #RRGGBB)")
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrTagExists       = errors.New("a tag with this name already exists; merge the tags instead")
+
+	tagColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+)
+
+type ITagUseCase interface {
+	ListTags(ctx context.Context, userID string) (*dto.TagsListResponse, error)
+	UpdateTag(ctx context.Context, userID string, name string, req dto.UpdateTagRequest) (*dto.TagChangeResponse, error)
+	MergeTags(ctx context.Context, userID string, req dto.MergeTagsRequest) (*dto.TagChangeResponse, error)
+	DeleteTag(ctx context.Context, userID string, name string) (*dto.TagChangeResponse, error)
+}
+
+type tagUseCase struct {
+	tagRepo       interfaces.ITagRepository
+	sessionRepo   interfaces.IFocusSessionRepository
+	seriesRepo    interfaces.ISessionSeriesRepository
+	rollupUseCase IRollupUseCase
+}
+
+func NewTagUseCase(
+	tagRepo interfaces.ITagRepository,
+	sessionRepo interfaces.IFocusSessionRepository,
+	seriesRepo interfaces.ISessionSeriesRepository,
+	rollupUseCase IRollupUseCase,
+) ITagUseCase {
+	return &tagUseCase{
+		tagRepo:       tagRepo,
+		sessionRepo:   sessionRepo,
+		seriesRepo:    seriesRepo,
+		rollupUseCase: rollupUseCase,
+	}
+}
+
+// ListTags returns the tags in use, most used first, followed by tags that
+// only have settings
+func (uc *tagUseCase) ListTags(ctx context.Context, userID string) (*dto.TagsListResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	usage, err := uc.sessionRepo.GetTagUsage(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := uc.tagRepo.GetByUserID(ctx, userObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		colors[tag.Name] = tag.Color
+	}
+
+	response := &dto.TagsListResponse{
+		Tags: make([]dto.TagResponse, 0, len(usage)+len(tags)),
+	}
+
+	used := make(map[string]bool, len(usage))
+	for _, u := range usage {
+		lastUsed := u.LastUsed
+		used[u.Name] = true
+		response.Tags = append(response.Tags, dto.TagResponse{
+			Name:     u.Name,
+			Color:    colors[u.Name],
+			Sessions: u.Sessions,
+			Minutes:  u.Minutes,
+			LastUsed: &lastUsed,
+		})
+	}
+
+	var unused []dto.TagResponse
+	for _, tag := range tags {
+		if !used[tag.Name] {
+			unused = append(unused, dto.TagResponse{
+				Name:  tag.Name,
+				Color: tag.Color,
+			})
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].Name < unused[j].Name
+	})
+	response.Tags = append(response.Tags, unused...)
+
+	return response, nil
+}
+
+// UpdateTag renames a tag on every session and series and moves its
+// settings, and sets its color. Renaming onto a tag that already exists is
+// refused; MergeTags does that.
+func (uc *tagUseCase) UpdateTag(ctx context.Context, userID string, name string, req dto.UpdateTagRequest) (*dto.TagChangeResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	name = strings.TrimSpace(name)
+	newName := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrInvalidTagName
+	}
+
+	var color string
+	if req.Color != nil && *req.Color != "" {
+		if !tagColorPattern.MatchString(*req.Color) {
+			return nil, ErrInvalidTagColor
+		}
+		color = strings.ToLower(*req.Color)
+	}
+
+	tag, exists, err := uc.findTag(ctx, userObjID, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTagNotFound
+	}
+
+	response := &dto.TagChangeResponse{Name: name}
+	if tag != nil {
+		response.Color = tag.Color
+	}
+
+	if newName != "" && newName != name {
+		_, taken, err := uc.findTag(ctx, userObjID, newName)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, ErrTagExists
+		}
+
+		updated, err := uc.sessionRepo.ReplaceTag(ctx, userObjID, name, newName)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := uc.seriesRepo.ReplaceTag(ctx, userObjID, name, newName); err != nil {
+			return nil, err
+		}
+
+		if err := uc.tagRepo.Rename(ctx, userObjID, name, newName); err != nil {
+			return nil, err
+		}
+
+		response.Name = newName
+		response.SessionsUpdated = int(updated)
+		uc.rebuildRollups(ctx, userObjID, updated)
+	}
+
+	if req.Color != nil {
+		now := time.Now()
+		if err := uc.tagRepo.Upsert(ctx, &entity.Tag{
+			UserID:    userObjID,
+			Name:      response.Name,
+			Color:     color,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err != nil {
+			return nil, err
+		}
+		response.Color = color
+	}
+
+	return response, nil
+}
+
+// MergeTags replaces each source tag with the target on every session and
+// series. The target keeps its color, or takes the first source color if it
+// has none.
+func (uc *tagUseCase) MergeTags(ctx context.Context, userID string, req dto.MergeTagsRequest) (*dto.TagChangeResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	target := strings.TrimSpace(req.Target)
+	if target == "" {
+		return nil, ErrInvalidTagName
+	}
+
+	seen := map[string]bool{target: true}
+	var sources []string
+	for _, source := range req.Sources {
+		source = strings.TrimSpace(source)
+		if source == "" || seen[source] {
+			continue
+		}
+		seen[source] = true
+		sources = append(sources, source)
+	}
+
+	if len(sources) == 0 {
+		return nil, ErrInvalidTagName
+	}
+
+	response := &dto.TagChangeResponse{Name: target}
+	for _, source := range sources {
+		updated, err := uc.sessionRepo.ReplaceTag(ctx, userObjID, source, target)
+		if err != nil {
+			return nil, err
+		}
+		response.SessionsUpdated += int(updated)
+
+		if err := uc.seriesRepo.ReplaceTag(ctx, userObjID, source, target); err != nil {
+			return nil, err
+		}
+
+		if err := uc.tagRepo.Rename(ctx, userObjID, source, target); err != nil {
+			return nil, err
+		}
+	}
+	uc.rebuildRollups(ctx, userObjID, int64(response.SessionsUpdated))
+
+	tag, err := uc.tagRepo.GetByName(ctx, userObjID, target)
+	if err != nil {
+		return nil, err
+	}
+	if tag != nil {
+		response.Color = tag.Color
+	}
+
+	return response, nil
+}
+
+// DeleteTag removes a tag from every session and series and drops its
+// settings
+func (uc *tagUseCase) DeleteTag(ctx context.Context, userID string, name string) (*dto.TagChangeResponse, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidTagName
+	}
+
+	_, exists, err := uc.findTag(ctx, userObjID, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTagNotFound
+	}
+
+	updated, err := uc.sessionRepo.RemoveTag(ctx, userObjID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.seriesRepo.RemoveTag(ctx, userObjID, name); err != nil {
+		return nil, err
+	}
+
+	if err := uc.tagRepo.Delete(ctx, userObjID, []string{name}); err != nil {
+		return nil, err
+	}
+	uc.rebuildRollups(ctx, userObjID, updated)
+
+	return &dto.TagChangeResponse{
+		Name:            name,
+		SessionsUpdated: int(updated),
+	}, nil
+}
+
+// rebuildRollups recomputes the user's rollups, which are broken down by tag,
+// once a tag change updated any session. The change is saved by then, so a
+// failure is only logged.
+func (uc *tagUseCase) rebuildRollups(ctx context.Context, userID primitive.ObjectID, updated int64) {
+	if updated == 0 {
+		return
+	}
+
+	if err := uc.rollupUseCase.RebuildRollups(ctx, userID); err != nil {
+		log.Printf("Failed to rebuild daily rollups: %v", err)
+	}
+}
+
+// findTag returns the tag's settings, if any, and whether the tag exists at
+// all: on a session or as settings alone
+func (uc *tagUseCase) findTag(ctx context.Context, userID primitive.ObjectID, name string) (*entity.Tag, bool, error) {
+	tag, err := uc.tagRepo.GetByName(ctx, userID, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if tag != nil {
+		return tag, true, nil
+	}
+
+	count, err := uc.sessionRepo.CountByUserID(ctx, userID, entity.SessionFilter{Tags: []string{name}})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return nil, count > 0, nil
+}
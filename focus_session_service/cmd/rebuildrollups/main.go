@@ -0,0 +1,69 @@
+// Command rebuildrollups recomputes the daily rollups of completed sessions
+// from the sessions themselves, in each user's preferred timezone. Run it once
+// to fill the rollups of existing sessions, or whenever they may have drifted.
+//
+// It rebuilds the user given by -user, or every user with sessions.
+package main
+
+import (
+	"context"
+	"flag"
+	usecase "focusspot/focussessionservice/application/usecases"
+	"focusspot/focussessionservice/config"
+	"focusspot/focussessionservice/infrastructure/persistence/mongodb"
+	"log"
+	"os"
+	_ "time/tzdata" // the runtime image has no zoneinfo
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func main() {
+	user := flag.String("user", "", "ID of the user to rebuild (default: all users)")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	client, db, err := mongodb.NewMongoDBConnection(&cfg.MongoDB)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer mongodb.CloseMongoDBConnection(client)
+
+	sessionRepo := mongodb.NewMongoFocusSessionRepository(db)
+	rollupRepo := mongodb.NewMongoDailyRollupRepository(db)
+	preferencesRepo := mongodb.NewMongoUserPreferencesRepository(db)
+	rollupUseCase := usecase.NewRollupUseCase(rollupRepo, sessionRepo, preferencesRepo)
+
+	ctx := context.Background()
+
+	var userIDs []primitive.ObjectID
+	if *user != "" {
+		userID, err := primitive.ObjectIDFromHex(*user)
+		if err != nil {
+			log.Fatalf("Invalid user ID: %v", err)
+		}
+		userIDs = append(userIDs, userID)
+	} else {
+		userIDs, err = sessionRepo.GetUserIDs(ctx)
+		if err != nil {
+			log.Fatalf("Failed to list users: %v", err)
+		}
+	}
+
+	failed := 0
+	for _, userID := range userIDs {
+		if err := rollupUseCase.RebuildRollups(ctx, userID); err != nil {
+			log.Printf("Failed to rebuild rollups of user %s: %v", userID.Hex(), err)
+			failed++
+		}
+	}
+
+	log.Printf("Rebuilt daily rollups of %d users, %d failed", len(userIDs)-failed, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
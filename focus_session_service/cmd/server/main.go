@@ -5,6 +5,7 @@ import (
 	"fmt"
 	usecase "focusspot/focussessionservice/application/usecases"
 	"focusspot/focussessionservice/config"
+	"focusspot/focussessionservice/domain/entity"
 	"focusspot/focussessionservice/infrastructure/api/handler"
 	"focusspot/focussessionservice/infrastructure/api/router"
 	"focusspot/focussessionservice/infrastructure/persistence/mongodb"
@@ -13,6 +14,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // the runtime image has no zoneinfo
 
 	"log"
 
@@ -41,12 +43,51 @@ func main() {
 
 	// Setup repositories
 	sessionRepo := mongodb.NewMongoFocusSessionRepository(db)
+	seriesRepo := mongodb.NewMongoSessionSeriesRepository(db)
+	calendarFeedRepo := mongodb.NewMongoCalendarFeedRepository(db)
+	tagRepo := mongodb.NewMongoTagRepository(db)
+	goalRepo := mongodb.NewMongoGoalRepository(db)
+	streakRepo := mongodb.NewMongoStreakRepository(db)
+	achievementRepo := mongodb.NewMongoAchievementRepository(db)
+	preferencesRepo := mongodb.NewMongoUserPreferencesRepository(db)
+	rollupRepo := mongodb.NewMongoDailyRollupRepository(db)
+	locationRepo := mongodb.NewMongoLocationRepository(db)
+	reviewRepo := mongodb.NewMongoLocationReviewRepository(db)
+
+	// Load achievement rules
+	achievementRules, err := entity.ParseAchievementRules(cfg.Achievement.Rules)
+	if err != nil {
+		log.Fatalf("Failed to load achievement rules: %v", err)
+	}
 
 	// Setup usecases
-	sessionUseCase := usecase.NewFocusSessionUseCase(sessionRepo)
+	streakUseCase := usecase.NewStreakUseCase(streakRepo, sessionRepo, preferencesRepo)
+	rollupUseCase := usecase.NewRollupUseCase(rollupRepo, sessionRepo, preferencesRepo)
+	achievementUseCase := usecase.NewAchievementUseCase(achievementRules, achievementRepo, sessionRepo, streakUseCase)
+	sessionUseCase := usecase.NewFocusSessionUseCase(sessionRepo, preferencesRepo, rollupRepo, locationRepo, streakUseCase, achievementUseCase, rollupUseCase)
+	seriesUseCase := usecase.NewSessionSeriesUseCase(seriesRepo, sessionRepo, locationRepo, preferencesRepo, streakUseCase, achievementUseCase, rollupUseCase)
+	calendarUseCase := usecase.NewCalendarUseCase(sessionRepo, calendarFeedRepo, preferencesRepo)
+	importUseCase := usecase.NewSessionImportUseCase(sessionRepo, preferencesRepo, streakUseCase, achievementUseCase, rollupUseCase)
+	exportUseCase := usecase.NewSessionExportUseCase(sessionRepo, preferencesRepo)
+	tagUseCase := usecase.NewTagUseCase(tagRepo, sessionRepo, seriesRepo, rollupUseCase)
+	preferencesUseCase := usecase.NewPreferencesUseCase(preferencesRepo)
+	goalUseCase := usecase.NewGoalUseCase(goalRepo, sessionRepo, preferencesRepo)
+	locationUseCase := usecase.NewLocationUseCase(locationRepo, sessionRepo, preferencesRepo)
+	reviewUseCase := usecase.NewLocationReviewUseCase(reviewRepo, locationRepo, sessionRepo)
 
 	// Setup handlers
 	sessionHandler := handler.NewFocusSessionHandler(sessionUseCase)
+	seriesHandler := handler.NewSessionSeriesHandler(seriesUseCase)
+	calendarHandler := handler.NewCalendarHandler(calendarUseCase)
+	importHandler := handler.NewSessionImportHandler(importUseCase)
+	exportHandler := handler.NewSessionExportHandler(exportUseCase)
+	tagHandler := handler.NewTagHandler(tagUseCase)
+	goalHandler := handler.NewGoalHandler(goalUseCase)
+	streakHandler := handler.NewStreakHandler(streakUseCase)
+	achievementHandler := handler.NewAchievementHandler(achievementUseCase)
+	preferencesHandler := handler.NewPreferencesHandler(preferencesUseCase)
+	locationHandler := handler.NewLocationHandler(locationUseCase)
+	reviewHandler := handler.NewLocationReviewHandler(reviewUseCase)
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
@@ -64,7 +105,7 @@ func main() {
 	})
 
 	// Setup routes
-	router.SetupRoutes(app, sessionHandler, tokenMaker)
+	router.SetupRoutes(app, sessionHandler, seriesHandler, calendarHandler, importHandler, exportHandler, tagHandler, goalHandler, streakHandler, achievementHandler, preferencesHandler, locationHandler, reviewHandler, tokenMaker)
 
 	// Start server in a goroutine
 	go func() {
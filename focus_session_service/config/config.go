@@ -1,6 +1,7 @@
 package config
 
 import (
+	_ "embed"
 	"fmt"
 	"os"
 	"strconv"
@@ -13,6 +14,7 @@ type Config struct {
 	Server      ServerConfig
 	MongoDB     MongoDBConfig
 	JWT         JWTConfig
+	Achievement AchievementConfig
 }
 
 // ServerConfig stores configuration for web server
@@ -36,6 +38,17 @@ type JWTConfig struct {
 	RefreshTokenDuration time.Duration
 }
 
+// AchievementConfig stores the achievement rules as JSON
+type AchievementConfig struct {
+	Rules []byte
+}
+
+// defaultAchievementRules are used unless ACHIEVEMENT_RULES_FILE names
+// another file
+//
+//go:embed achievements.json
+var defaultAchievementRules []byte
+
 // LoadConfigs loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	config := &Config{
@@ -57,6 +70,15 @@ func LoadConfig() (*Config, error) {
 		},
 	}
 
+	config.Achievement.Rules = defaultAchievementRules
+	if path := getEnv("ACHIEVEMENT_RULES_FILE", ""); path != "" {
+		rules, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read achievement rules: %w", err)
+		}
+		config.Achievement.Rules = rules
+	}
+
 	// Validate JWT secret key
 	if len(config.JWT.SecretKey) < 32 {
 		return nil, fmt.Errorf("JWT_SECRET_KEY must be at least 32 characters long")
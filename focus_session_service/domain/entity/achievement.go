@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AchievementMetric string
+
+const (
+	AchievementSessions     AchievementMetric = "sessions"      // completed sessions
+	AchievementFocusMinutes AchievementMetric = "focus_minutes" // minutes of completed sessions
+	AchievementStreakDays   AchievementMetric = "streak_days"   // longest streak; filters do not apply
+)
+
+// AchievementRule declares a badge. It unlocks once the metric over the
+// user's completed sessions matching the filter reaches the target.
+type AchievementRule struct {
+	Key         string            `json:"key"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Metric      AchievementMetric `json:"metric"`
+	Target      int               `json:"target"`
+	Filter      AchievementFilter `json:"filter,omitempty"`
+}
+
+// AchievementFilter narrows the sessions a rule counts. Zero values do not
+// filter.
+type AchievementFilter struct {
+	Tag          string `json:"tag,omitempty"`
+	LocationType string `json:"locationType,omitempty"`
+	MinFocus     *int   `json:"minFocus,omitempty"`
+	MinRating    *int   `json:"minRating,omitempty"`
+	MinDuration  *int   `json:"minDuration,omitempty"` // actual minutes
+}
+
+// Achievement is a badge a user unlocked
+type Achievement struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID  `json:"userId" bson:"userId"`
+	Key        string              `json:"key" bson:"key"`
+	SessionID  *primitive.ObjectID `json:"sessionId,omitempty" bson:"sessionId,omitempty"` // the session that unlocked it
+	UnlockedAt time.Time           `json:"unlockedAt" bson:"unlockedAt"`
+}
+
+// ParseAchievementRules reads a JSON array of rules and checks them
+func ParseAchievementRules(data []byte) ([]AchievementRule, error) {
+	var rules []AchievementRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid achievement rules: %w", err)
+	}
+
+	keys := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("achievement %q: %w", rule.Key, err)
+		}
+		if keys[rule.Key] {
+			return nil, fmt.Errorf("achievement %q: duplicate key", rule.Key)
+		}
+		keys[rule.Key] = true
+	}
+
+	return rules, nil
+}
+
+func (m AchievementMetric) IsValid() bool {
+	return m == AchievementSessions || m == AchievementFocusMinutes || m == AchievementStreakDays
+}
+
+func (r AchievementRule) Validate() error {
+	switch {
+	case r.Key == "":
+		return errors.New("key is required")
+	case r.Name == "":
+		return errors.New("name is required")
+	case !r.Metric.IsValid():
+		return errors.New("invalid metric (use sessions, focus_minutes or streak_days)")
+	case r.Target <= 0:
+		return errors.New("target must be positive")
+	}
+	return nil
+}
+
+// Matches reports whether a completed session counts towards the rule
+func (r AchievementRule) Matches(session *FocusSession) bool {
+	if session.Status != StatusCompleted {
+		return false
+	}
+	if r.Metric == AchievementStreakDays {
+		return true
+	}
+
+	f := r.Filter
+	if f.Tag != "" {
+		found := false
+		for _, tag := range session.Tags {
+			if tag == f.Tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.LocationType != "" && (session.LocationDetails == nil || session.LocationDetails.Type != f.LocationType) {
+		return false
+	}
+
+	return atLeast(session.Focus, f.MinFocus) &&
+		atLeast(session.Rating, f.MinRating) &&
+		atLeast(session.ActualDuration, f.MinDuration)
+}
+
+// SessionFilter returns the filter selecting the sessions the rule counts
+func (r AchievementRule) SessionFilter() SessionFilter {
+	filter := SessionFilter{
+		Statuses:     []SessionStatus{StatusCompleted},
+		LocationType: r.Filter.LocationType,
+		MinFocus:     r.Filter.MinFocus,
+		MinRating:    r.Filter.MinRating,
+		MinDuration:  r.Filter.MinDuration,
+	}
+	if r.Filter.Tag != "" {
+		filter.Tags = []string{r.Filter.Tag}
+	}
+	return filter
+}
+
+func atLeast(value, min *int) bool {
+	return min == nil || (value != nil && *value >= *min)
+}
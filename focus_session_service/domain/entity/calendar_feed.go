@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarFeed holds the secret token of a user's iCalendar subscription URL.
+// Calendar apps cannot send an Authorization header, so the token stands in for it.
+type CalendarFeed struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	Token     string             `json:"token" bson:"token"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,265 @@
+package entity
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RollupDateLayout formats the day of a rollup
+const RollupDateLayout = "2006-01-02"
+
+// DailyRollup sums up the completed sessions a user started on one day of
+// their timezone, in total and grouped by hour, location, location type and
+// tag. A session counts towards each of its tags.
+type DailyRollup struct {
+	ID             primitive.ObjectID       `json:"id" bson:"_id,omitempty"`
+	UserID         primitive.ObjectID       `json:"userId" bson:"userId"`
+	Day            string                   `json:"day" bson:"day"`           // YYYY-MM-DD
+	Timezone       string                   `json:"timezone" bson:"timezone"` // IANA name the day is in
+	Totals         RollupMetrics            `json:"totals" bson:"totals"`
+	ByHour         map[string]RollupMetrics `json:"byHour" bson:"byHour"` // "0" to "23"
+	ByLocation     map[string]RollupMetrics `json:"byLocation" bson:"byLocation"`
+	ByLocationType map[string]RollupMetrics `json:"byLocationType" bson:"byLocationType"`
+	ByTag          map[string]RollupMetrics `json:"byTag" bson:"byTag"`
+	UpdatedAt      time.Time                `json:"updatedAt" bson:"updatedAt"`
+}
+
+// RollupMetrics are the sums of a group of completed sessions. Averages are
+// the sums over their counts, since not every session has every metric.
+type RollupMetrics struct {
+	Sessions          int `json:"sessions" bson:"sessions"`
+	Minutes           int `json:"minutes" bson:"minutes"`
+	Pomodoros         int `json:"pomodoros" bson:"pomodoros"`
+	RatingSum         int `json:"ratingSum" bson:"ratingSum"`
+	RatingCount       int `json:"ratingCount" bson:"ratingCount"`
+	FocusSum          int `json:"focusSum" bson:"focusSum"`
+	FocusCount        int `json:"focusCount" bson:"focusCount"`
+	EnergySum         int `json:"energySum" bson:"energySum"`
+	EnergyCount       int `json:"energyCount" bson:"energyCount"`
+	MoodSum           int `json:"moodSum" bson:"moodSum"`
+	MoodCount         int `json:"moodCount" bson:"moodCount"`
+	DistractionsSum   int `json:"distractionsSum" bson:"distractionsSum"`
+	DistractionsCount int `json:"distractionsCount" bson:"distractionsCount"`
+
+	// Productivity scores by scorer version, for every built-in scorer
+	Scores map[string]ScoreSum `json:"scores" bson:"scores"`
+}
+
+// ScoreSum adds up the productivity scores of a group of sessions
+type ScoreSum struct {
+	Sum      float64 `json:"sum" bson:"sum"`
+	Positive int     `json:"positive" bson:"positive"` // sessions scoring above zero
+}
+
+// IsRolledUp reports whether a session counts towards rollups: it must be
+// completed, with an actual duration, and not deleted
+func IsRolledUp(session *FocusSession) bool {
+	return session != nil && session.Active && session.Status == StatusCompleted && session.ActualDuration != nil
+}
+
+// NewSessionRollup returns the rollup of a single session on its day in loc,
+// scaled by sign: 1 adds the session to a stored rollup, -1 takes it away.
+// Sessions that are not rolled up give nil.
+func NewSessionRollup(session *FocusSession, loc *time.Location, sign int) *DailyRollup {
+	if !IsRolledUp(session) {
+		return nil
+	}
+
+	localStart := session.StartTime.In(loc)
+	metrics := sessionMetrics(session, sign)
+
+	rollup := newDailyRollup(session.UserID, localStart.Format(RollupDateLayout), loc)
+	rollup.Totals = metrics
+	rollup.ByHour[strconv.Itoa(localStart.Hour())] = metrics
+
+	if session.LocationDetails != nil {
+		rollup.ByLocation[session.LocationDetails.Name] = metrics
+		if session.LocationDetails.Type != "" {
+			rollup.ByLocationType[session.LocationDetails.Type] = metrics
+		}
+	}
+
+	for _, tag := range session.Tags {
+		rollup.ByTag[tag] = rollup.ByTag[tag].plus(metrics)
+	}
+
+	return rollup
+}
+
+// ComputeDailyRollups folds a user's sessions into one rollup per day in loc,
+// ordered by day
+func ComputeDailyRollups(sessions []*FocusSession, loc *time.Location) []*DailyRollup {
+	byDay := make(map[string]*DailyRollup)
+	for _, session := range sessions {
+		rollup := NewSessionRollup(session, loc, 1)
+		if rollup == nil {
+			continue
+		}
+
+		if existing, ok := byDay[rollup.Day]; ok {
+			existing.Add(rollup)
+		} else {
+			byDay[rollup.Day] = rollup
+		}
+	}
+
+	rollups := make([]*DailyRollup, 0, len(byDay))
+	for _, rollup := range byDay {
+		rollups = append(rollups, rollup)
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		return rollups[i].Day < rollups[j].Day
+	})
+	return rollups
+}
+
+// Add adds another rollup of the same day
+func (r *DailyRollup) Add(other *DailyRollup) {
+	r.Totals = r.Totals.plus(other.Totals)
+	addGroups(r.ByHour, other.ByHour)
+	addGroups(r.ByLocation, other.ByLocation)
+	addGroups(r.ByLocationType, other.ByLocationType)
+	addGroups(r.ByTag, other.ByTag)
+}
+
+// Score returns the sum of the scores of a scorer version
+func (m RollupMetrics) Score(version string) ScoreSum {
+	return m.Scores[version]
+}
+
+func (m RollupMetrics) plus(other RollupMetrics) RollupMetrics {
+	sum := RollupMetrics{
+		Sessions:          m.Sessions + other.Sessions,
+		Minutes:           m.Minutes + other.Minutes,
+		Pomodoros:         m.Pomodoros + other.Pomodoros,
+		RatingSum:         m.RatingSum + other.RatingSum,
+		RatingCount:       m.RatingCount + other.RatingCount,
+		FocusSum:          m.FocusSum + other.FocusSum,
+		FocusCount:        m.FocusCount + other.FocusCount,
+		EnergySum:         m.EnergySum + other.EnergySum,
+		EnergyCount:       m.EnergyCount + other.EnergyCount,
+		MoodSum:           m.MoodSum + other.MoodSum,
+		MoodCount:         m.MoodCount + other.MoodCount,
+		DistractionsSum:   m.DistractionsSum + other.DistractionsSum,
+		DistractionsCount: m.DistractionsCount + other.DistractionsCount,
+		Scores:            make(map[string]ScoreSum, len(m.Scores)),
+	}
+
+	for version, score := range m.Scores {
+		sum.Scores[version] = score
+	}
+	for version, score := range other.Scores {
+		total := sum.Scores[version]
+		total.Sum += score.Sum
+		total.Positive += score.Positive
+		sum.Scores[version] = total
+	}
+
+	return sum
+}
+
+func addGroups(groups, other map[string]RollupMetrics) {
+	for key, metrics := range other {
+		groups[key] = groups[key].plus(metrics)
+	}
+}
+
+func newDailyRollup(userID primitive.ObjectID, day string, loc *time.Location) *DailyRollup {
+	return &DailyRollup{
+		UserID:         userID,
+		Day:            day,
+		Timezone:       loc.String(),
+		ByHour:         make(map[string]RollupMetrics),
+		ByLocation:     make(map[string]RollupMetrics),
+		ByLocationType: make(map[string]RollupMetrics),
+		ByTag:          make(map[string]RollupMetrics),
+	}
+}
+
+// sessionMetrics returns the metrics of one session, scored by every
+// built-in scorer
+func sessionMetrics(session *FocusSession, sign int) RollupMetrics {
+	metrics := RollupMetrics{
+		Sessions: sign,
+		Minutes:  sign * *session.ActualDuration,
+		Scores:   make(map[string]ScoreSum, len(scorers)),
+	}
+
+	if session.Pomodoro != nil {
+		metrics.Pomodoros = sign * session.Pomodoro.CompletedPomodoros
+	}
+
+	sumOf := func(value *int, sum, count *int) {
+		if value != nil {
+			*sum = sign * *value
+			*count = sign
+		}
+	}
+	sumOf(session.Rating, &metrics.RatingSum, &metrics.RatingCount)
+	sumOf(session.Focus, &metrics.FocusSum, &metrics.FocusCount)
+	sumOf(session.Energy, &metrics.EnergySum, &metrics.EnergyCount)
+	sumOf(session.Mood, &metrics.MoodSum, &metrics.MoodCount)
+	sumOf(session.Distractions, &metrics.DistractionsSum, &metrics.DistractionsCount)
+
+	for _, scorer := range scorers {
+		// Sessions without a score do not count towards productivity
+		var score ScoreSum
+		if value := scorer.Score(session); value > 0 {
+			score = ScoreSum{Sum: float64(sign) * value, Positive: sign}
+		}
+		metrics.Scores[scorer.Version()] = score
+	}
+
+	return metrics
+}
+
+// ComputeProductivityTrendsFromRollups groups daily rollups into the periods
+// of the window from startDate to endDate, in the location of startDate. The
+// rollups must be in that location, and the window must start and end on
+// day boundaries for the trends to match those of the sessions.
+func ComputeProductivityTrendsFromRollups(rollups []*DailyRollup, period Period, startDate, endDate time.Time, scorerVersion string) *ProductivityTrends {
+	loc := startDate.Location()
+
+	starts := period.Starts(startDate, endDate)
+	totals := make(map[int64]RollupMetrics, len(starts))
+	for _, rollup := range rollups {
+		day, err := time.ParseInLocation(RollupDateLayout, rollup.Day, loc)
+		if err != nil || day.Before(startDate) || day.After(endDate) {
+			continue
+		}
+
+		key := period.Start(day).Unix()
+		totals[key] = totals[key].plus(rollup.Totals)
+	}
+
+	average := func(sum, count int) float64 {
+		if count <= 0 {
+			return 0
+		}
+		return float64(sum) / float64(count)
+	}
+
+	trends := NewProductivityTrends(period)
+	for _, start := range starts {
+		metrics := totals[start.Unix()]
+
+		point := TrendPoint{
+			Sessions: metrics.Sessions,
+			Duration: metrics.Minutes,
+			Rating:   average(metrics.RatingSum, metrics.RatingCount),
+			Focus:    average(metrics.FocusSum, metrics.FocusCount),
+			Energy:   average(metrics.EnergySum, metrics.EnergyCount),
+			Mood:     average(metrics.MoodSum, metrics.MoodCount),
+		}
+		if score := metrics.Score(scorerVersion); score.Positive > 0 {
+			point.Productivity = score.Sum / float64(score.Positive)
+		}
+
+		trends.Add(start, point)
+	}
+
+	return trends
+}
@@ -17,6 +17,13 @@ type FocusSession struct {
 	Duration        int                 `json:"duration" bson:"duration"` // minutes
 	ActualDuration  *int                `json:"actualDuration" bson:"actualDuration"`
 	Status          SessionStatus       `json:"status" bson:"status"`
+	Mode            SessionMode         `json:"mode,omitempty" bson:"mode,omitempty"` // default: single
+	Pomodoro        *PomodoroState      `json:"pomodoro,omitempty" bson:"pomodoro,omitempty"`
+	Pauses          []PauseInterval     `json:"pauses,omitempty" bson:"pauses,omitempty"`
+	SeriesID        *primitive.ObjectID `json:"seriesId,omitempty" bson:"seriesId,omitempty"`
+	OccurrenceTime  *time.Time          `json:"occurrenceTime,omitempty" bson:"occurrenceTime,omitempty"` // original start within the series
+	SeriesOverride  bool                `json:"seriesOverride,omitempty" bson:"seriesOverride,omitempty"` // edited apart from its series
+	ExternalUID     string              `json:"externalUid,omitempty" bson:"externalUid,omitempty"`       // UID of the imported calendar event
 	LocationID      *primitive.ObjectID `json:"locationId,omitempty" bson:"locationId,omitempty"`
 	LocationDetails *LocationDetails    `json:"locationDetails,omitempty" bson:"locationDetails,omitempty"`
 	Tags            []string            `json:"tags,omitempty" bson:"tags"`
@@ -37,10 +44,18 @@ type SessionStatus string
 const (
 	StatusPlanned   SessionStatus = "planned"
 	StatusActive    SessionStatus = "active"
+	StatusPaused    SessionStatus = "paused"
 	StatusCompleted SessionStatus = "completed"
 	StatusCancelled SessionStatus = "cancelled"
 )
 
+// PauseInterval is a break taken during a session. ResumedAt is nil while the
+// session is still paused.
+type PauseInterval struct {
+	PausedAt  time.Time  `json:"pausedAt" bson:"pausedAt"`
+	ResumedAt *time.Time `json:"resumedAt,omitempty" bson:"resumedAt,omitempty"`
+}
+
 type LocationDetails struct {
 	Name      string  `json:"name" bson:"name"`
 	Address   string  `json:"address,omitempty" bson:"address,omitempty"`
@@ -49,6 +64,72 @@ type LocationDetails struct {
 	Type      string  `json:"type,omitempty" bson:"type,omitempty"` // coffee shop, library, etc.
 }
 
+// IsPaused reports whether the session has an open pause interval
+func (s *FocusSession) IsPaused() bool {
+	return len(s.Pauses) > 0 && s.Pauses[len(s.Pauses)-1].ResumedAt == nil
+}
+
+// PausedDuration returns the total time spent paused up to the given time.
+// An open pause interval is counted until `until`.
+func (s *FocusSession) PausedDuration(until time.Time) time.Duration {
+	var total time.Duration
+	for _, pause := range s.Pauses {
+		end := until
+		if pause.ResumedAt != nil {
+			end = *pause.ResumedAt
+		}
+		if end.After(pause.PausedAt) {
+			total += end.Sub(pause.PausedAt)
+		}
+	}
+	return total
+}
+
+// FocusedMinutes returns the minutes spent focusing between StartTime and
+// endTime, leaving out any paused time
+func (s *FocusSession) FocusedMinutes(endTime time.Time) int {
+	focused := endTime.Sub(s.StartTime) - s.PausedDuration(endTime)
+	if focused < 0 {
+		return 0
+	}
+	return int(focused.Minutes())
+}
+
+// CompleteAt marks the session completed at endTime, with ActualDuration
+// measured from the session's own start and end rather than the clock.
+// Backfilled sessions rely on this.
+func (s *FocusSession) CompleteAt(endTime time.Time) {
+	actualDuration := s.FocusedMinutes(endTime)
+	s.Status = StatusCompleted
+	s.EndTime = &endTime
+	s.ActualDuration = &actualDuration
+}
+
+// PomodoroProgress returns the pomodoro phase at the given time. Paused time
+// stops the pomodoro clock. It returns nil for sessions not in pomodoro mode.
+func (s *FocusSession) PomodoroProgress(now time.Time) *PomodoroProgress {
+	if s.Mode != ModePomodoro || s.Pomodoro == nil {
+		return nil
+	}
+
+	elapsed := now.Sub(s.StartTime) - s.PausedDuration(now)
+	progress := s.Pomodoro.ProgressAt(elapsed)
+	return &progress
+}
+
+// SyncPomodoro stores the pomodoro phase reached at the given time on the session
+func (s *FocusSession) SyncPomodoro(now time.Time) *PomodoroProgress {
+	progress := s.PomodoroProgress(now)
+	if progress == nil {
+		return nil
+	}
+
+	s.Pomodoro.Phase = progress.Phase
+	s.Pomodoro.CycleIndex = progress.CycleIndex
+	s.Pomodoro.CompletedPomodoros = progress.CompletedPomodoros
+	return progress
+}
+
 // GetCalculateProductivityScore calculates the productivity score for a session
 func (s *FocusSession) CalculateProductivityScore() float64 {
 	if s.Status != StatusCompleted || s.ActualDuration == nil || s.Rating == nil {
@@ -0,0 +1,177 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GoalPeriod string
+
+const (
+	GoalDaily   GoalPeriod = "daily"
+	GoalWeekly  GoalPeriod = "weekly" // weeks start on Monday
+	GoalMonthly GoalPeriod = "monthly"
+)
+
+type GoalMetric string
+
+const (
+	MetricFocusMinutes      GoalMetric = "focus_minutes"
+	MetricCompletedSessions GoalMetric = "completed_sessions"
+	MetricAverageFocus      GoalMetric = "average_focus"
+)
+
+// Goal is a target a user sets for each day, week or month. Only completed
+// sessions count, optionally only those with a tag or at a location type.
+type Goal struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `json:"userId" bson:"userId"`
+	Title        string             `json:"title" bson:"title"`
+	Metric       GoalMetric         `json:"metric" bson:"metric"`
+	Period       GoalPeriod         `json:"period" bson:"period"`
+	Target       float64            `json:"target" bson:"target"`
+	Tag          string             `json:"tag,omitempty" bson:"tag,omitempty"`
+	LocationType string             `json:"locationType,omitempty" bson:"locationType,omitempty"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt" bson:"updatedAt"`
+	Active       bool               `json:"active" bson:"active"` // default: true
+}
+
+// GoalProgress is how far a goal is in one period
+type GoalProgress struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Current     float64
+	Sessions    int
+	Achieved    bool
+
+	// Projected is the value expected by the end of the period at the
+	// current pace. Averages are not extrapolated.
+	Projected float64
+
+	// ProjectedCompletion is when the target will be reached at the current
+	// pace, if that is before the period ends. It is set to the current time
+	// once the goal is achieved.
+	ProjectedCompletion *time.Time
+}
+
+func (p GoalPeriod) IsValid() bool {
+	return p == GoalDaily || p == GoalWeekly || p == GoalMonthly
+}
+
+func (m GoalMetric) IsValid() bool {
+	return m == MetricFocusMinutes || m == MetricCompletedSessions || m == MetricAverageFocus
+}
+
+// Bounds returns the period containing t, in t's location. The end is
+// exclusive.
+func (p GoalPeriod) Bounds(t time.Time) (time.Time, time.Time) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch p {
+	case GoalWeekly:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case GoalMonthly:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 1, 0)
+	default:
+		return day, day.AddDate(0, 0, 1)
+	}
+}
+
+// Matches reports whether a session counts towards the goal
+func (g *Goal) Matches(session *FocusSession) bool {
+	if session.Status != StatusCompleted || session.ActualDuration == nil {
+		return false
+	}
+
+	if g.Tag != "" {
+		found := false
+		for _, tag := range session.Tags {
+			if tag == g.Tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if g.LocationType != "" {
+		if session.LocationDetails == nil || session.LocationDetails.Type != g.LocationType {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Progress measures the goal over the period containing now. Sessions that
+// do not count towards the goal are ignored.
+func (g *Goal) Progress(sessions []*FocusSession, now time.Time) GoalProgress {
+	start, end := g.Period.Bounds(now)
+	progress := GoalProgress{
+		PeriodStart: start,
+		PeriodEnd:   end,
+	}
+
+	var minutes, focusTotal, focusCount int
+	for _, session := range sessions {
+		if !g.Matches(session) || session.StartTime.Before(start) || !session.StartTime.Before(end) {
+			continue
+		}
+
+		progress.Sessions++
+		minutes += *session.ActualDuration
+		if session.Focus != nil {
+			focusTotal += *session.Focus
+			focusCount++
+		}
+	}
+
+	switch g.Metric {
+	case MetricFocusMinutes:
+		progress.Current = float64(minutes)
+	case MetricCompletedSessions:
+		progress.Current = float64(progress.Sessions)
+	case MetricAverageFocus:
+		if focusCount > 0 {
+			progress.Current = float64(focusTotal) / float64(focusCount)
+		}
+	}
+
+	progress.Achieved = g.Target > 0 && progress.Current >= g.Target
+	progress.Projected = progress.Current
+
+	if g.Metric == MetricAverageFocus {
+		if progress.Achieved {
+			progress.ProjectedCompletion = &now
+		}
+		return progress
+	}
+
+	// Cumulative metrics are extrapolated at the pace so far
+	elapsed := now.Sub(start)
+	if elapsed <= 0 || now.After(end) {
+		return progress
+	}
+
+	pace := progress.Current / elapsed.Hours()
+	progress.Projected = pace * end.Sub(start).Hours()
+
+	switch {
+	case progress.Achieved:
+		progress.ProjectedCompletion = &now
+	case pace > 0:
+		eta := start.Add(time.Duration(g.Target / pace * float64(time.Hour)))
+		if eta.Before(end) {
+			progress.ProjectedCompletion = &eta
+		}
+	}
+
+	return progress
+}
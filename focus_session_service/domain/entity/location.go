@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LocationType string
+
+const (
+	LocationCafe      LocationType = "cafe"
+	LocationLibrary   LocationType = "library"
+	LocationCoworking LocationType = "coworking"
+	LocationOffice    LocationType = "office"
+	LocationHome      LocationType = "home"
+	LocationPark      LocationType = "park"
+	LocationOther     LocationType = "other"
+)
+
+// Location is a focus spot sessions can take place at. Spots are shared by
+// all users, except private ones, which only the user who added them sees;
+// home spots are always private. Only the user who added a spot may change or
+// delete it.
+type Location struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name         string             `json:"name" bson:"name"`
+	Address      string             `json:"address,omitempty" bson:"address,omitempty"`
+	Coordinates  GeoPoint           `json:"coordinates" bson:"coordinates"`
+	Type         LocationType       `json:"type" bson:"type"`
+	Amenities    []string           `json:"amenities" bson:"amenities"` // wifi, outlets, quiet, etc.
+	OpeningHours []OpeningHours     `json:"openingHours" bson:"openingHours"`
+	Timezone     string             `json:"timezone,omitempty" bson:"timezone,omitempty"` // IANA name opening hours are in
+	Private      bool               `json:"private" bson:"private"`
+	CreatedBy    primitive.ObjectID `json:"createdBy" bson:"createdBy"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt" bson:"updatedAt"`
+	Active       bool               `json:"active" bson:"active"` // default: true
+}
+
+// GeoPoint is a GeoJSON point, longitude first
+type GeoPoint struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// OpeningHours is when a spot is open on one day of the week. A closing time
+// at or before the opening time is on the next day.
+type OpeningHours struct {
+	Day   time.Weekday `json:"day" bson:"day"`
+	Open  string       `json:"open" bson:"open"`   // HH:MM
+	Close string       `json:"close" bson:"close"` // HH:MM
+}
+
+// LocationFilter narrows a listing of spots. Zero fields do not filter,
+// except Viewer: spots private to anyone else are always left out.
+type LocationFilter struct {
+	Viewer    primitive.ObjectID // the user listing the spots
+	Type      LocationType
+	Name      string // case-insensitive substring of the name
+	Amenities []string
+	CreatedBy *primitive.ObjectID
+}
+
+// ClockLayout formats the times of opening hours
+const ClockLayout = "15:04"
+
+func (t LocationType) IsValid() bool {
+	switch t {
+	case LocationCafe, LocationLibrary, LocationCoworking, LocationOffice, LocationHome, LocationPark, LocationOther:
+		return true
+	}
+	return false
+}
+
+func NewGeoPoint(latitude, longitude float64) GeoPoint {
+	return GeoPoint{
+		Type:        "Point",
+		Coordinates: []float64{longitude, latitude},
+	}
+}
+
+func (p GeoPoint) Latitude() float64 {
+	if len(p.Coordinates) < 2 {
+		return 0
+	}
+	return p.Coordinates[1]
+}
+
+func (p GeoPoint) Longitude() float64 {
+	if len(p.Coordinates) < 2 {
+		return 0
+	}
+	return p.Coordinates[0]
+}
+
+// IsValid reports whether the point is on the globe
+func (p GeoPoint) IsValid() bool {
+	latitude, longitude := p.Latitude(), p.Longitude()
+	return len(p.Coordinates) == 2 &&
+		latitude >= -90 && latitude <= 90 &&
+		longitude >= -180 && longitude <= 180
+}
+
+func (h OpeningHours) IsValid() bool {
+	if h.Day < time.Sunday || h.Day > time.Saturday {
+		return false
+	}
+	if _, err := time.Parse(ClockLayout, h.Open); err != nil {
+		return false
+	}
+	_, err := time.Parse(ClockLayout, h.Close)
+	return err == nil
+}
+
+// ApplyPrivacy makes home spots private; their coordinates are where the
+// user lives
+func (l *Location) ApplyPrivacy() {
+	if l.Type == LocationHome {
+		l.Private = true
+	}
+}
+
+// Details returns the location details sessions at the spot keep, as the
+// spot was when the session was saved
+func (l *Location) Details() *LocationDetails {
+	return &LocationDetails{
+		Name:      l.Name,
+		Address:   l.Address,
+		Latitude:  l.Coordinates.Latitude(),
+		Longitude: l.Coordinates.Longitude(),
+		Type:      string(l.Type),
+	}
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NearbyLocation is a spot and how far it is from where the user searched
+type NearbyLocation struct {
+	Location `bson:",inline"`
+	Distance float64 `json:"distance" bson:"distance"` // meters
+}
+
+// LocationProductivity sums up the completed sessions of one or more users
+// at one spot
+type LocationProductivity struct {
+	LocationID          primitive.ObjectID `json:"locationId" bson:"_id"`
+	Users               int                `json:"users" bson:"users"` // distinct users the sessions are from
+	Sessions            int                `json:"sessions" bson:"sessions"`
+	Minutes             int                `json:"minutes" bson:"minutes"`
+	AverageProductivity float64            `json:"averageProductivity" bson:"productivity"` // average score of the sessions
+	LastVisit           time.Time          `json:"lastVisit" bson:"lastVisit"`
+}
+
+// ComputeLocationProductivity sums up the active completed sessions at each
+// saved spot in memory, ordered by spot. The database aggregation must give
+// the same sums.
+func ComputeLocationProductivity(sessions []*FocusSession, scorer ProductivityScorer) []*LocationProductivity {
+	byLocation := make(map[primitive.ObjectID]*LocationProductivity)
+	scores := make(map[primitive.ObjectID]float64)
+	users := make(map[primitive.ObjectID]map[primitive.ObjectID]bool)
+
+	for _, session := range sessions {
+		if !session.Active || session.Status != StatusCompleted || session.ActualDuration == nil || session.LocationID == nil {
+			continue
+		}
+
+		id := *session.LocationID
+		productivity, ok := byLocation[id]
+		if !ok {
+			productivity = &LocationProductivity{LocationID: id}
+			byLocation[id] = productivity
+			users[id] = make(map[primitive.ObjectID]bool)
+		}
+
+		users[id][session.UserID] = true
+		productivity.Sessions++
+		productivity.Minutes += *session.ActualDuration
+		if session.StartTime.After(productivity.LastVisit) {
+			productivity.LastVisit = session.StartTime
+		}
+		scores[id] += scorer.Score(session)
+	}
+
+	result := make([]*LocationProductivity, 0, len(byLocation))
+	for id, productivity := range byLocation {
+		productivity.AverageProductivity = scores[id] / float64(productivity.Sessions)
+		productivity.Users = len(users[id])
+		result = append(result, productivity)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LocationID.Hex() < result[j].LocationID.Hex()
+	})
+	return result
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reviews rate each aspect of a spot from 1 to 5
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 5
+)
+
+type ReviewAspect string
+
+const (
+	AspectNoise    ReviewAspect = "noise"    // 1 quiet to 5 loud
+	AspectWiFi     ReviewAspect = "wifi"     // 1 none or unusable to 5 fast and reliable
+	AspectOutlets  ReviewAspect = "outlets"  // 1 none to 5 at every seat
+	AspectSeating  ReviewAspect = "seating"  // 1 uncomfortable to 5 comfortable
+	AspectCrowding ReviewAspect = "crowding" // 1 empty to 5 packed
+)
+
+// ReviewAspects lists the aspects in the order responses show them
+var ReviewAspects = []ReviewAspect{AspectNoise, AspectWiFi, AspectOutlets, AspectSeating, AspectCrowding}
+
+// LocationReview is one user's ratings of a spot. Each user has at most one
+// review per spot; aspects they did not rate are nil.
+type LocationReview struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	LocationID primitive.ObjectID `json:"locationId" bson:"locationId"`
+	UserID     primitive.ObjectID `json:"userId" bson:"userId"`
+	Noise      *int               `json:"noise,omitempty" bson:"noise,omitempty"`
+	WiFi       *int               `json:"wifi,omitempty" bson:"wifi,omitempty"`
+	Outlets    *int               `json:"outlets,omitempty" bson:"outlets,omitempty"`
+	Seating    *int               `json:"seating,omitempty" bson:"seating,omitempty"`
+	Crowding   *int               `json:"crowding,omitempty" bson:"crowding,omitempty"`
+	Text       string             `json:"text,omitempty" bson:"text,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// LocationRatings are the average ratings of a spot over its reviews. An
+// aspect no review rated is nil.
+type LocationRatings struct {
+	LocationID primitive.ObjectID `json:"locationId" bson:"_id"`
+	Reviews    int                `json:"reviews" bson:"reviews"`
+	Noise      *float64           `json:"noise,omitempty" bson:"noise"`
+	WiFi       *float64           `json:"wifi,omitempty" bson:"wifi"`
+	Outlets    *float64           `json:"outlets,omitempty" bson:"outlets"`
+	Seating    *float64           `json:"seating,omitempty" bson:"seating"`
+	Crowding   *float64           `json:"crowding,omitempty" bson:"crowding"`
+}
+
+// RatingRange keeps the spots whose average rating of an aspect is between
+// Min and Max, inclusive
+type RatingRange struct {
+	Min float64
+	Max float64
+}
+
+func (a ReviewAspect) IsValid() bool {
+	switch a {
+	case AspectNoise, AspectWiFi, AspectOutlets, AspectSeating, AspectCrowding:
+		return true
+	}
+	return false
+}
+
+// Rating returns the review's rating of the aspect, nil when not rated
+func (r *LocationReview) Rating(aspect ReviewAspect) *int {
+	switch aspect {
+	case AspectNoise:
+		return r.Noise
+	case AspectWiFi:
+		return r.WiFi
+	case AspectOutlets:
+		return r.Outlets
+	case AspectSeating:
+		return r.Seating
+	case AspectCrowding:
+		return r.Crowding
+	}
+	return nil
+}
+
+// Average returns the spot's average rating of the aspect, nil when no
+// review rated it
+func (r *LocationRatings) Average(aspect ReviewAspect) *float64 {
+	switch aspect {
+	case AspectNoise:
+		return r.Noise
+	case AspectWiFi:
+		return r.WiFi
+	case AspectOutlets:
+		return r.Outlets
+	case AspectSeating:
+		return r.Seating
+	case AspectCrowding:
+		return r.Crowding
+	}
+	return nil
+}
+
+// Matches reports whether the spot's average ratings are within every range.
+// A spot nobody rated on an aspect does not match a range on it.
+func (r *LocationRatings) Matches(ranges map[ReviewAspect]RatingRange) bool {
+	for aspect, bounds := range ranges {
+		average := r.Average(aspect)
+		if average == nil || *average < bounds.Min || *average > bounds.Max {
+			return false
+		}
+	}
+	return true
+}
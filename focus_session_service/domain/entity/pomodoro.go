@@ -0,0 +1,126 @@
+package entity
+
+import "time"
+
+type SessionMode string
+
+const (
+	ModeSingle   SessionMode = "single"
+	ModePomodoro SessionMode = "pomodoro"
+)
+
+type PomodoroPhase string
+
+const (
+	PhaseWork       PomodoroPhase = "work"
+	PhaseShortBreak PomodoroPhase = "short_break"
+	PhaseLongBreak  PomodoroPhase = "long_break"
+)
+
+// Default pomodoro configuration, in minutes
+const (
+	DefaultPomodoroWork       = 25
+	DefaultPomodoroShortBreak = 5
+	DefaultPomodoroLongBreak  = 15
+	DefaultPomodoroCycles     = 4
+)
+
+// PomodoroState holds the pomodoro configuration of a session and the phase
+// it was in when last synced
+type PomodoroState struct {
+	WorkDuration       int           `json:"workDuration" bson:"workDuration"`             // minutes
+	ShortBreakDuration int           `json:"shortBreakDuration" bson:"shortBreakDuration"` // minutes
+	LongBreakDuration  int           `json:"longBreakDuration" bson:"longBreakDuration"`   // minutes
+	Cycles             int           `json:"cycles" bson:"cycles"`                         // work phases before a long break
+	Phase              PomodoroPhase `json:"phase" bson:"phase"`
+	CycleIndex         int           `json:"cycleIndex" bson:"cycleIndex"` // 1-based, within the current set
+	CompletedPomodoros int           `json:"completedPomodoros" bson:"completedPomodoros"`
+}
+
+// PomodoroProgress is the position within the pomodoro schedule at a point in time
+type PomodoroProgress struct {
+	Phase              PomodoroPhase
+	CycleIndex         int
+	CompletedPomodoros int
+	PhaseRemaining     time.Duration
+}
+
+// NewPomodoroState returns a pomodoro state, filling zero values with defaults
+func NewPomodoroState(work, shortBreak, longBreak, cycles int) *PomodoroState {
+	if work <= 0 {
+		work = DefaultPomodoroWork
+	}
+	if shortBreak <= 0 {
+		shortBreak = DefaultPomodoroShortBreak
+	}
+	if longBreak <= 0 {
+		longBreak = DefaultPomodoroLongBreak
+	}
+	if cycles <= 0 {
+		cycles = DefaultPomodoroCycles
+	}
+
+	return &PomodoroState{
+		WorkDuration:       work,
+		ShortBreakDuration: shortBreak,
+		LongBreakDuration:  longBreak,
+		Cycles:             cycles,
+		Phase:              PhaseWork,
+		CycleIndex:         1,
+	}
+}
+
+// SetDuration returns the planned focus length of one set, without the
+// closing long break
+func (p *PomodoroState) SetDuration() int {
+	return p.Cycles*p.WorkDuration + (p.Cycles-1)*p.ShortBreakDuration
+}
+
+// ProgressAt returns the phase reached after `elapsed` of running time.
+// A set is Cycles work phases separated by short breaks and followed by a
+// long break, and sets repeat until the session ends.
+func (p *PomodoroState) ProgressAt(elapsed time.Duration) PomodoroProgress {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	work := time.Duration(p.WorkDuration) * time.Minute
+	shortBreak := time.Duration(p.ShortBreakDuration) * time.Minute
+	longBreak := time.Duration(p.LongBreakDuration) * time.Minute
+	setLength := time.Duration(p.Cycles)*work + time.Duration(p.Cycles-1)*shortBreak + longBreak
+
+	completedSets := int(elapsed / setLength)
+	remaining := elapsed % setLength
+	completed := completedSets * p.Cycles
+
+	for cycle := 1; cycle <= p.Cycles; cycle++ {
+		if remaining < work {
+			return PomodoroProgress{
+				Phase:              PhaseWork,
+				CycleIndex:         cycle,
+				CompletedPomodoros: completed,
+				PhaseRemaining:     work - remaining,
+			}
+		}
+		remaining -= work
+		completed++
+
+		phase, breakLength := PhaseShortBreak, shortBreak
+		if cycle == p.Cycles {
+			phase, breakLength = PhaseLongBreak, longBreak
+		}
+
+		if remaining < breakLength {
+			return PomodoroProgress{
+				Phase:              phase,
+				CycleIndex:         cycle,
+				CompletedPomodoros: completed,
+				PhaseRemaining:     breakLength - remaining,
+			}
+		}
+		remaining -= breakLength
+	}
+
+	// Unreachable: remaining is always shorter than a full set
+	return PomodoroProgress{Phase: PhaseWork, CycleIndex: 1, CompletedPomodoros: completed, PhaseRemaining: work}
+}
@@ -0,0 +1,360 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// timesOfDay lists the times of day in the order of the clock, from the
+// early morning
+var timesOfDay = []TimeOfDay{EarlyMorning, LateMorning, Afternoon, Evening, Night}
+
+// TrendPoint holds the values of one period of trends
+type TrendPoint struct {
+	Sessions     int
+	Duration     int // in minutes
+	Rating       float64
+	Focus        float64
+	Energy       float64
+	Mood         float64
+	Productivity float64
+}
+
+// NewProductivityStats returns empty stats with their maps made
+func NewProductivityStats() *ProductivityStats {
+	return &ProductivityStats{
+		ProductivityByDay:          make(map[time.Weekday]float64),
+		ProductivityByTime:         make(map[TimeOfDay]float64),
+		ProductivityByLocation:     make(map[string]float64),
+		ProductivityByLocationType: make(map[string]float64),
+		ProductivityByTag:          make(map[string]float64),
+	}
+}
+
+// SetMostProductive picks the best day, time of day, location, location type
+// and tag from the productivity maps. Only positive scores count, and ties go
+// to the earliest day or time of day, or the first name in sort order.
+func (s *ProductivityStats) SetMostProductive() {
+	var best float64
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if score := s.ProductivityByDay[day]; score > best {
+			best = score
+			s.MostProductiveDay = day
+		}
+	}
+
+	best = 0
+	for _, tod := range timesOfDay {
+		if score := s.ProductivityByTime[tod]; score > best {
+			best = score
+			s.MostProductiveTime = tod
+		}
+	}
+
+	s.MostProductiveLocation = mostProductiveName(s.ProductivityByLocation)
+	s.MostProductiveLocationType = mostProductiveName(s.ProductivityByLocationType)
+	s.MostProductiveTag = mostProductiveName(s.ProductivityByTag)
+}
+
+func mostProductiveName(scores map[string]float64) string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var best float64
+	var bestName string
+	for _, name := range names {
+		if scores[name] > best {
+			best = scores[name]
+			bestName = name
+		}
+	}
+	return bestName
+}
+
+// NewProductivityTrends returns trends without periods
+func NewProductivityTrends(period Period) *ProductivityTrends {
+	return &ProductivityTrends{
+		Period:       period,
+		Starts:       []time.Time{},
+		Dates:        []string{},
+		Sessions:     []int{},
+		Durations:    []int{},
+		Ratings:      []float64{},
+		Focus:        []float64{},
+		Energy:       []float64{},
+		Mood:         []float64{},
+		Productivity: []float64{},
+	}
+}
+
+// Add appends the period starting at start
+func (t *ProductivityTrends) Add(start time.Time, point TrendPoint) {
+	t.Starts = append(t.Starts, start)
+	t.Dates = append(t.Dates, t.Period.Label(start))
+	t.Sessions = append(t.Sessions, point.Sessions)
+	t.Durations = append(t.Durations, point.Duration)
+	t.Ratings = append(t.Ratings, point.Rating)
+	t.Focus = append(t.Focus, point.Focus)
+	t.Energy = append(t.Energy, point.Energy)
+	t.Mood = append(t.Mood, point.Mood)
+	t.Productivity = append(t.Productivity, point.Productivity)
+}
+
+// ComputeProductivityStats aggregates sessions in memory. Only active
+// completed and cancelled sessions count, and days and times of day are read
+// in loc. The database aggregation must give the same stats.
+func ComputeProductivityStats(sessions []*FocusSession, loc *time.Location, scorer ProductivityScorer) *ProductivityStats {
+	stats := NewProductivityStats()
+
+	// Counter structures for analysis
+	type scoreCounter struct {
+		Count      int
+		TotalScore float64
+	}
+
+	dayCounter := make(map[time.Weekday]*scoreCounter)
+	timeCounter := make(map[TimeOfDay]*scoreCounter)
+	locationCounter := make(map[string]*scoreCounter)
+	locationTypeCounter := make(map[string]*scoreCounter)
+	tagCounter := make(map[string]*scoreCounter)
+
+	count := func(counters map[string]*scoreCounter, key string, score float64) {
+		if _, exists := counters[key]; !exists {
+			counters[key] = &scoreCounter{}
+		}
+		counters[key].Count++
+		counters[key].TotalScore += score
+	}
+
+	var totalRating, ratingCount int
+	var totalFocus, focusCount int
+	var totalEnergy, energyCount int
+	var totalMood, moodCount int
+	var totalDistract, distractCount int
+	var totalScore float64
+
+	for _, session := range sessions {
+		if !session.Active {
+			continue
+		}
+
+		switch session.Status {
+		case StatusCancelled:
+			stats.TotalSessions++
+			stats.CancelledSessions++
+			continue
+		case StatusCompleted:
+			stats.TotalSessions++
+		default:
+			continue
+		}
+
+		// Skip sessions with no actual duration
+		if session.ActualDuration == nil {
+			continue
+		}
+
+		stats.CompletedSessions++
+		stats.TotalDuration += *session.ActualDuration
+
+		if session.Pomodoro != nil {
+			stats.PomodorosCompleted += session.Pomodoro.CompletedPomodoros
+		}
+
+		// Calculate productivity score
+		prodScore := scorer.Score(session)
+		totalScore += prodScore
+
+		localStart := session.StartTime.In(loc)
+		day := localStart.Weekday()
+		if _, exists := dayCounter[day]; !exists {
+			dayCounter[day] = &scoreCounter{}
+		}
+		dayCounter[day].Count++
+		dayCounter[day].TotalScore += prodScore
+
+		tod := GetTimeOfDay(localStart)
+		if _, exists := timeCounter[tod]; !exists {
+			timeCounter[tod] = &scoreCounter{}
+		}
+		timeCounter[tod].Count++
+		timeCounter[tod].TotalScore += prodScore
+
+		if session.LocationDetails != nil {
+			count(locationCounter, session.LocationDetails.Name, prodScore)
+			if session.LocationDetails.Type != "" {
+				count(locationTypeCounter, session.LocationDetails.Type, prodScore)
+			}
+		}
+
+		// A session counts towards each of its tags
+		for _, tag := range session.Tags {
+			count(tagCounter, tag, prodScore)
+		}
+
+		// Collect metrics
+		if session.Rating != nil {
+			totalRating += *session.Rating
+			ratingCount++
+		}
+		if session.Focus != nil {
+			totalFocus += *session.Focus
+			focusCount++
+		}
+		if session.Energy != nil {
+			totalEnergy += *session.Energy
+			energyCount++
+		}
+		if session.Mood != nil {
+			totalMood += *session.Mood
+			moodCount++
+		}
+		if session.Distractions != nil {
+			totalDistract += *session.Distractions
+			distractCount++
+		}
+	}
+
+	// Calculate averages
+	if stats.CompletedSessions > 0 {
+		stats.AverageProductivity = totalScore / float64(stats.CompletedSessions)
+	}
+	if ratingCount > 0 {
+		stats.AverageRating = float64(totalRating) / float64(ratingCount)
+	}
+	if focusCount > 0 {
+		stats.AverageFocus = float64(totalFocus) / float64(focusCount)
+	}
+	if energyCount > 0 {
+		stats.AverageEnergy = float64(totalEnergy) / float64(energyCount)
+	}
+	if moodCount > 0 {
+		stats.AverageMood = float64(totalMood) / float64(moodCount)
+	}
+	if distractCount > 0 {
+		stats.AverageDistractions = float64(totalDistract) / float64(distractCount)
+	}
+	stats.DistractionSessions = distractCount
+
+	// Average productivity score of each group
+	for day, counter := range dayCounter {
+		stats.ProductivityByDay[day] = counter.TotalScore / float64(counter.Count)
+	}
+	for tod, counter := range timeCounter {
+		stats.ProductivityByTime[tod] = counter.TotalScore / float64(counter.Count)
+	}
+	for name, counter := range locationCounter {
+		stats.ProductivityByLocation[name] = counter.TotalScore / float64(counter.Count)
+	}
+	for locType, counter := range locationTypeCounter {
+		stats.ProductivityByLocationType[locType] = counter.TotalScore / float64(counter.Count)
+	}
+	for tag, counter := range tagCounter {
+		stats.ProductivityByTag[tag] = counter.TotalScore / float64(counter.Count)
+	}
+
+	stats.SetMostProductive()
+	return stats
+}
+
+// ComputeProductivityTrends groups sessions into the periods of the window
+// from startDate to endDate in memory, in the location of startDate. Only
+// active completed sessions count. The database aggregation must give the
+// same trends.
+func ComputeProductivityTrends(sessions []*FocusSession, period Period, startDate, endDate time.Time, scorer ProductivityScorer) *ProductivityTrends {
+	loc := startDate.Location()
+
+	type periodData struct {
+		Sessions          int
+		TotalDuration     int
+		TotalRating       int
+		RatingCount       int
+		TotalFocus        int
+		FocusCount        int
+		TotalEnergy       int
+		EnergyCount       int
+		TotalMood         int
+		MoodCount         int
+		TotalProductivity float64
+		ProductivityCount int
+	}
+
+	// Create entries for all periods in range, keyed by their start
+	starts := period.Starts(startDate, endDate)
+	periodMap := make(map[int64]*periodData, len(starts))
+	for _, start := range starts {
+		periodMap[start.Unix()] = &periodData{}
+	}
+
+	// Group sessions into periods
+	for _, session := range sessions {
+		if !session.Active || session.Status != StatusCompleted || session.ActualDuration == nil {
+			continue
+		}
+		if session.StartTime.Before(startDate) || session.StartTime.After(endDate) {
+			continue
+		}
+
+		data, exists := periodMap[period.Start(session.StartTime.In(loc)).Unix()]
+		if !exists {
+			continue
+		}
+
+		data.Sessions++
+		data.TotalDuration += *session.ActualDuration
+
+		if session.Rating != nil {
+			data.TotalRating += *session.Rating
+			data.RatingCount++
+		}
+		if session.Focus != nil {
+			data.TotalFocus += *session.Focus
+			data.FocusCount++
+		}
+		if session.Energy != nil {
+			data.TotalEnergy += *session.Energy
+			data.EnergyCount++
+		}
+		if session.Mood != nil {
+			data.TotalMood += *session.Mood
+			data.MoodCount++
+		}
+
+		// Sessions without a score do not count towards productivity
+		if prodScore := scorer.Score(session); prodScore > 0 {
+			data.TotalProductivity += prodScore
+			data.ProductivityCount++
+		}
+	}
+
+	average := func(total, count int) float64 {
+		if count == 0 {
+			return 0
+		}
+		return float64(total) / float64(count)
+	}
+
+	trends := NewProductivityTrends(period)
+	for _, start := range starts {
+		data := periodMap[start.Unix()]
+
+		point := TrendPoint{
+			Sessions: data.Sessions,
+			Duration: data.TotalDuration,
+			Rating:   average(data.TotalRating, data.RatingCount),
+			Focus:    average(data.TotalFocus, data.FocusCount),
+			Energy:   average(data.TotalEnergy, data.EnergyCount),
+			Mood:     average(data.TotalMood, data.MoodCount),
+		}
+		if data.ProductivityCount > 0 {
+			point.Productivity = data.TotalProductivity / float64(data.ProductivityCount)
+		}
+
+		trends.Add(start, point)
+	}
+
+	return trends
+}
@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HeatmapLevels is the number of intensity levels of a heatmap day, from 0
+// for no focus time to HeatmapLevels-1 for the most
+const HeatmapLevels = 5
+
+// ProductivityHeatmap holds a year of focus time, day by day, for a
+// contribution-style grid, and the average productivity of every hour of the
+// week over that year
+type ProductivityHeatmap struct {
+	Year int          `json:"year"`
+	Days []HeatmapDay `json:"days"` // every day of the year, in order
+
+	// Least minutes of each level from 1 up: the smallest, lower quartile,
+	// median and upper quartile of the days with focus time
+	LevelThresholds []int `json:"levelThresholds"`
+
+	// Indexed by weekday, Sunday first, then by hour of the day. Hours
+	// without a scored session are zero.
+	HourOfWeekProductivity [7][24]float64 `json:"hourOfWeekProductivity"`
+	HourOfWeekSessions     [7][24]int     `json:"hourOfWeekSessions"` // completed sessions
+}
+
+type HeatmapDay struct {
+	Date     string `json:"date"` // YYYY-MM-DD
+	Minutes  int    `json:"minutes"`
+	Sessions int    `json:"sessions"` // completed sessions
+	Level    int    `json:"level"`
+}
+
+// ComputeProductivityHeatmap lays out the daily rollups of year, which must
+// be in loc, with productivity by the scorer of scorerVersion
+func ComputeProductivityHeatmap(rollups []*DailyRollup, year int, loc *time.Location, scorerVersion string) *ProductivityHeatmap {
+	byDay := make(map[string]*DailyRollup, len(rollups))
+	for _, rollup := range rollups {
+		byDay[rollup.Day] = rollup
+	}
+
+	heatmap := &ProductivityHeatmap{Year: year}
+	var scores [7][24]ScoreSum
+	var active []int
+
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	for day := first; day.Year() == year; day = day.AddDate(0, 0, 1) {
+		heatmapDay := HeatmapDay{Date: day.Format(RollupDateLayout)}
+
+		rollup, ok := byDay[heatmapDay.Date]
+		if ok {
+			heatmapDay.Minutes = rollup.Totals.Minutes
+			heatmapDay.Sessions = rollup.Totals.Sessions
+			if heatmapDay.Minutes > 0 {
+				active = append(active, heatmapDay.Minutes)
+			}
+
+			weekday := day.Weekday()
+			for hour := 0; hour < 24; hour++ {
+				metrics := rollup.ByHour[strconv.Itoa(hour)]
+				score := metrics.Score(scorerVersion)
+				heatmap.HourOfWeekSessions[weekday][hour] += metrics.Sessions
+				scores[weekday][hour].Sum += score.Sum
+				scores[weekday][hour].Positive += score.Positive
+			}
+		}
+
+		heatmap.Days = append(heatmap.Days, heatmapDay)
+	}
+
+	for weekday := range scores {
+		for hour, score := range scores[weekday] {
+			if score.Positive > 0 {
+				heatmap.HourOfWeekProductivity[weekday][hour] = score.Sum / float64(score.Positive)
+			}
+		}
+	}
+
+	heatmap.LevelThresholds = levelThresholds(active)
+	for i := range heatmap.Days {
+		heatmap.Days[i].Level = heatmap.level(heatmap.Days[i].Minutes)
+	}
+
+	return heatmap
+}
+
+// level returns the highest level whose threshold the minutes reach
+func (h *ProductivityHeatmap) level(minutes int) int {
+	if minutes <= 0 {
+		return 0
+	}
+
+	level := 1
+	for i, threshold := range h.LevelThresholds {
+		if minutes >= threshold {
+			level = i + 1
+		}
+	}
+	return level
+}
+
+// levelThresholds splits the minutes of the active days into quartiles. A
+// year without focus time has no thresholds.
+func levelThresholds(minutes []int) []int {
+	if len(minutes) == 0 {
+		return []int{}
+	}
+
+	sorted := append([]int(nil), minutes...)
+	sort.Ints(sorted)
+
+	thresholds := make([]int, HeatmapLevels-1)
+	for i := range thresholds {
+		thresholds[i] = sorted[i*(len(sorted)-1)/len(thresholds)]
+	}
+	return thresholds
+}
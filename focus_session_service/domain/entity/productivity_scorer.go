@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"math"
+	"sort"
+)
+
+// Scorer names users can choose between
+const (
+	ScorerClassic            = "classic"
+	ScorerWeighted           = "weighted"
+	ScorerDurationNormalized = "duration_normalized"
+)
+
+// ProductivityScorer scores a completed session from 0 to 10. Version names
+// the formula, so that scores from different formulas are not compared. A
+// changed formula must get a new version.
+type ProductivityScorer interface {
+	Name() string
+	Version() string
+	Score(session *FocusSession) float64
+}
+
+// DefaultScorer is used for users who have not chosen one
+var DefaultScorer ProductivityScorer = ClassicScorer{}
+
+var scorers = map[string]ProductivityScorer{
+	ScorerClassic:            ClassicScorer{},
+	ScorerWeighted:           WeightedScorer{Weights: DefaultMetricWeights},
+	ScorerDurationNormalized: DurationNormalizedScorer{},
+}
+
+// ScorerByName returns the scorer with the given name
+func ScorerByName(name string) (ProductivityScorer, bool) {
+	scorer, ok := scorers[name]
+	return scorer, ok
+}
+
+// ScorerNames returns the names of all scorers, sorted
+func ScorerNames() []string {
+	names := make([]string, 0, len(scorers))
+	for name := range scorers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ClassicScorer is the original formula of CalculateProductivityScore
+type ClassicScorer struct{}
+
+func (ClassicScorer) Name() string    { return ScorerClassic }
+func (ClassicScorer) Version() string { return "classic-v1" }
+
+func (ClassicScorer) Score(session *FocusSession) float64 {
+	return session.CalculateProductivityScore()
+}
+
+// MetricWeights are the share of each self-reported metric in a weighted
+// score. Only the metrics a session has are counted, and their weights are
+// scaled to add up to one.
+type MetricWeights struct {
+	Rating       float64
+	Focus        float64
+	Energy       float64
+	Mood         float64
+	Distractions float64
+}
+
+var DefaultMetricWeights = MetricWeights{
+	Rating:       0.3,
+	Focus:        0.3,
+	Energy:       0.1,
+	Mood:         0.1,
+	Distractions: 0.2,
+}
+
+// WeightedScorer averages the session's metrics, each scaled to 0-1, with
+// the given weights. Duration does not count.
+type WeightedScorer struct {
+	Weights MetricWeights
+}
+
+func (WeightedScorer) Name() string    { return ScorerWeighted }
+func (WeightedScorer) Version() string { return "weighted-v1" }
+
+func (w WeightedScorer) Score(session *FocusSession) float64 {
+	if session.Status != StatusCompleted || session.ActualDuration == nil {
+		return 0
+	}
+
+	var total, weights float64
+	add := func(value float64, weight float64) {
+		total += value * weight
+		weights += weight
+	}
+
+	if session.Rating != nil {
+		add(scale(*session.Rating, 1, 5), w.Weights.Rating)
+	}
+	if session.Focus != nil {
+		add(scale(*session.Focus, 1, 10), w.Weights.Focus)
+	}
+	if session.Energy != nil {
+		add(scale(*session.Energy, 1, 10), w.Weights.Energy)
+	}
+	if session.Mood != nil {
+		add(scale(*session.Mood, 1, 10), w.Weights.Mood)
+	}
+	if session.Distractions != nil {
+		// No distractions scores 1, ten or more score 0
+		add(1-math.Min(float64(*session.Distractions)/10.0, 1.0), w.Weights.Distractions)
+	}
+
+	if weights == 0 {
+		return 0
+	}
+	return total / weights * 10
+}
+
+// DurationNormalizedScorer is the classic formula with a duration adjustment
+// that grows with the gap between planned and actual duration, from -1 at
+// half the plan to +1 at one and a half times the plan. Ending on plan is
+// neither rewarded nor penalized.
+type DurationNormalizedScorer struct{}
+
+func (DurationNormalizedScorer) Name() string    { return ScorerDurationNormalized }
+func (DurationNormalizedScorer) Version() string { return "duration_normalized-v1" }
+
+func (DurationNormalizedScorer) Score(session *FocusSession) float64 {
+	if session.Status != StatusCompleted || session.ActualDuration == nil || session.Rating == nil {
+		return 0
+	}
+
+	score := float64(*session.Rating)
+
+	if session.Duration > 0 {
+		completionRatio := float64(*session.ActualDuration) / float64(session.Duration)
+		score += math.Max(-1, math.Min(1, (completionRatio-1)*2))
+	}
+
+	if session.Focus != nil {
+		score += float64(*session.Focus) / 10.0 * 2.0
+	}
+
+	if session.Distractions != nil && *session.Distractions > 0 {
+		score -= math.Min(float64(*session.Distractions)/10.0, 1.0)
+	}
+
+	return math.Max(0, math.Min(10, score))
+}
+
+// scale maps a value in [min, max] to [0, 1]
+func scale(value, min, max int) float64 {
+	return math.Max(0, math.Min(1, float64(value-min)/float64(max-min)))
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type ProductivityStats struct {
 	TotalSessions       int     `json:"totalSessions"`
@@ -12,6 +15,11 @@ type ProductivityStats struct {
 	AverageEnergy       float64 `json:"averageEnergy"`
 	AverageMood         float64 `json:"averageMood"`
 	AverageDistractions float64 `json:"averageDistractions"`
+	DistractionSessions int     `json:"distractionSessions"` // completed sessions that recorded distractions
+	PomodorosCompleted  int     `json:"pomodorosCompleted"`
+
+	// Average productivity score of the completed sessions
+	AverageProductivity float64 `json:"averageProductivity"`
 
 	// Productivity by day of week (0=Sunday, 6=Saturday)
 	ProductivityByDay map[time.Weekday]float64 `json:"productivityByDay"`
@@ -28,6 +36,10 @@ type ProductivityStats struct {
 	// Productivity by location type
 	ProductivityByLocationType map[string]float64 `json:"productivityByLocationType"`
 	MostProductiveLocationType string             `json:"mostProductiveLocationType"`
+
+	// Productivity by tag. A session counts towards each of its tags.
+	ProductivityByTag map[string]float64 `json:"productivityByTag"`
+	MostProductiveTag string             `json:"mostProductiveTag"`
 }
 
 func (s *ProductivityStats) GetAverageDuration() float64 {
@@ -37,7 +49,38 @@ func (s *ProductivityStats) GetAverageDuration() float64 {
     return float64(s.TotalDuration) / float64(s.CompletedSessions)
 }
 
+// Weights of the parts of the overall productivity score
+const (
+    overallProductivityWeight = 0.5
+    overallFocusWeight        = 0.2
+    overallDistractionsWeight = 0.15
+    overallCompletionWeight   = 0.15
+)
+
+// GetOverallProductivityScore combines the average productivity score with
+// focus, distractions and the share of sessions finished rather than
+// cancelled, from 0 to 10. Parts without data are left out and the weights of
+// the others scaled up.
 func (s *ProductivityStats) GetOverallProductivityScore() float64 {
-    // TODO: Tính điểm năng suất tổng thể dựa trên nhiều yếu tố
-    return 0
-}
\ No newline at end of file
+    if s.CompletedSessions == 0 {
+        return 0
+    }
+
+    var total, weights float64
+    add := func(value float64, weight float64) {
+        total += math.Max(0, math.Min(1, value)) * weight
+        weights += weight
+    }
+
+    add(s.AverageProductivity/10.0, overallProductivityWeight)
+    if s.AverageFocus > 0 {
+        add((s.AverageFocus-1)/9.0, overallFocusWeight)
+    }
+    // No distractions scores 1, ten or more score 0
+    if s.DistractionSessions > 0 {
+        add(1-s.AverageDistractions/10.0, overallDistractionsWeight)
+    }
+    add(float64(s.CompletedSessions)/float64(s.CompletedSessions+s.CancelledSessions), overallCompletionWeight)
+
+    return total / weights * 10
+}
@@ -1,33 +1,275 @@
 package entity
 
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
 type Period string
 
 const (
-	Daily   Period = "daily"
-	Weekly  Period = "weekly"
-	Monthly Period = "monthly"
+	Daily     Period = "daily"
+	Weekly    Period = "weekly" // weeks start on Monday
+	Monthly   Period = "monthly"
+	Quarterly Period = "quarterly"
+	Yearly    Period = "yearly"
 )
 
+// ProductivityTrends holds one value per period of the window, oldest first.
+// Periods without sessions are kept, with zero values.
 type ProductivityTrends struct {
-	Period       Period    `json:"period"` // daily, weekly, monthly
-	Dates        []string  `json:"dates"`
-	Durations    []int     `json:"durations"` // in minutes
-	Ratings      []float64 `json:"ratings"`
-	Focus        []float64 `json:"focus"`
-	Energy       []float64 `json:"energy"`
-	Mood         []float64 `json:"mood"`
-	Productivity []float64 `json:"productivity"` // overall productivity score
+	Period       Period      `json:"period"`
+	Starts       []time.Time `json:"starts"` // start of each period
+	Dates        []string    `json:"dates"`
+	Sessions     []int       `json:"sessions"`  // completed sessions
+	Durations    []int       `json:"durations"` // in minutes
+	Ratings      []float64   `json:"ratings"`
+	Focus        []float64   `json:"focus"`
+	Energy       []float64   `json:"energy"`
+	Mood         []float64   `json:"mood"`
+	Productivity []float64   `json:"productivity"` // overall productivity score
+}
+
+func (p Period) IsValid() bool {
+	switch p {
+	case Daily, Weekly, Monthly, Quarterly, Yearly:
+		return true
+	}
+	return false
+}
+
+// Start returns the start of the period containing t, in t's location
+func (p Period) Start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch p {
+	case Weekly:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case Quarterly:
+		month := (t.Month()-1)/3*3 + 1
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+	case Yearly:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// Add moves t by n periods
+func (p Period) Add(t time.Time, n int) time.Time {
+	switch p {
+	case Weekly:
+		return t.AddDate(0, 0, 7*n)
+	case Monthly:
+		return t.AddDate(0, n, 0)
+	case Quarterly:
+		return t.AddDate(0, 3*n, 0)
+	case Yearly:
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// Starts returns the start of every period the window from startDate to
+// endDate touches, in the location of startDate
+func (p Period) Starts(startDate, endDate time.Time) []time.Time {
+	var starts []time.Time
+	for current := p.Start(startDate); !current.After(endDate); current = p.Add(current, 1) {
+		starts = append(starts, current)
+	}
+	return starts
+}
+
+// Label formats the period starting at start for display
+func (p Period) Label(start time.Time) string {
+	switch p {
+	case Weekly:
+		// Format as "Jan 02-08" (start and end of week)
+		weekEnd := start.AddDate(0, 0, 6)
+		if start.Month() == weekEnd.Month() {
+			return fmt.Sprintf("%s %d-%d", start.Format("Jan"), start.Day(), weekEnd.Day())
+		}
+		return fmt.Sprintf("%s-%s", start.Format("Jan 02"), weekEnd.Format("Jan 02"))
+	case Monthly:
+		return start.Format("Jan 2006")
+	case Quarterly:
+		return fmt.Sprintf("Q%d %d", (start.Month()-1)/3+1, start.Year())
+	case Yearly:
+		return start.Format("2006")
+	default:
+		return start.Format("Jan 02")
+	}
+}
+
+// TotalSessions adds up the sessions of every period
+func (t *ProductivityTrends) TotalSessions() int {
+	total := 0
+	for _, sessions := range t.Sessions {
+		total += sessions
+	}
+	return total
+}
+
+// TotalDuration adds up the minutes of every period
+func (t *ProductivityTrends) TotalDuration() int {
+	total := 0
+	for _, duration := range t.Durations {
+		total += duration
+	}
+	return total
+}
+
+// Significance level below which a trend is taken as real
+const TrendSignificanceLevel = 0.05
+
+// TrendFit is the least-squares line through the productivity of the periods
+// that have any, against their position in the series
+type TrendFit struct {
+	Slope  float64 // change of productivity per period
+	PValue float64 // chance of a slope this steep if productivity were flat
+	Points int
 }
 
-// Có thể thêm các helper methods
+// GetAverageProductivity averages the periods that have a productivity score
 func (t *ProductivityTrends) GetAverageProductivity() float64 {
-	// TODO: Tính trung bình của mảng Productivity
-	// ...
-	return 0
+	var total float64
+	var count int
+	for _, productivity := range t.Productivity {
+		if productivity > 0 {
+			total += productivity
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// Fit fits a line through the periods that have a productivity score. The
+// p-value is that of a two-sided t-test of the slope, and is 1 when there are
+// too few periods to tell.
+func (t *ProductivityTrends) Fit() TrendFit {
+	var xs, ys []float64
+	for i, productivity := range t.Productivity {
+		if productivity > 0 {
+			xs = append(xs, float64(i))
+			ys = append(ys, productivity)
+		}
+	}
+
+	fit := TrendFit{PValue: 1, Points: len(xs)}
+	if fit.Points < 2 {
+		return fit
+	}
+
+	n := float64(fit.Points)
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var sxx, sxy float64
+	for i := range xs {
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+		sxy += (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	fit.Slope = sxy / sxx
+
+	// Two points always fit exactly, so nothing can be said of the slope
+	if fit.Points < 3 {
+		return fit
+	}
+
+	var sse float64
+	for i := range xs {
+		residual := ys[i] - (meanY + fit.Slope*(xs[i]-meanX))
+		sse += residual * residual
+	}
+
+	df := n - 2
+	stdErr := math.Sqrt(sse / df / sxx)
+	switch {
+	case stdErr > 0:
+		fit.PValue = studentTwoSidedP(fit.Slope/stdErr, df)
+	case fit.Slope != 0:
+		fit.PValue = 0
+	}
+	return fit
 }
 
+// IsImproving reports whether productivity rises significantly over the
+// periods
 func (t *ProductivityTrends) IsImproving() bool {
-	// Phân tích xu hướng để xác định nếu năng suất đang tăng
-	// ...
-	return false
+	fit := t.Fit()
+	return fit.Slope > 0 && fit.PValue < TrendSignificanceLevel
+}
+
+// studentTwoSidedP is the chance of a t statistic at least as far from zero
+// as tValue, with df degrees of freedom
+func studentTwoSidedP(tValue, df float64) float64 {
+	return regularizedIncompleteBeta(df/2, 0.5, df/(df+tValue*tValue))
+}
+
+// regularizedIncompleteBeta evaluates I_x(a, b) by its continued fraction
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+
+	// The continued fraction converges fast only below this point, so use the
+	// symmetry I_x(a, b) = 1 - I_(1-x)(b, a) above it
+	if x > (a+1)/(a+b+2) {
+		return 1 - regularizedIncompleteBeta(b, a, 1-x)
+	}
+
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	lgammaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+
+	// Lentz's method
+	const tiny = 1e-30
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	f := d
+
+	for m := 1; m <= 200; m++ {
+		mf := float64(m)
+		for _, numerator := range []float64{
+			mf * (b - mf) * x / ((a + 2*mf - 1) * (a + 2*mf)),
+			-(a + mf) * (a + b + mf) * x / ((a + 2*mf) * (a + 2*mf + 1)),
+		} {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			f *= c * d
+		}
+		if math.Abs(c*d-1) < 1e-12 {
+			break
+		}
+	}
+
+	return front * f / a
 }
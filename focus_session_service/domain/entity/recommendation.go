@@ -0,0 +1,342 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MinRecommendationSessions is how many sessions a group needs before
+// recommendations rest on its average score
+const MinRecommendationSessions = 3
+
+// MinCommunityUsers is how many other users must have been to a spot before
+// their scores there are used, so that no one user's productivity shows
+const MinCommunityUsers = 3
+
+// maxCommunityWeight caps how many sessions other users' scores count as
+// when blended with the user's own
+const maxCommunityWeight = 10
+
+type RecommendationSource string
+
+const (
+	SourceHistory   RecommendationSource = "history"   // the user's own sessions
+	SourceCommunity RecommendationSource = "community" // other users' sessions at the spot
+	SourceBlended   RecommendationSource = "blended"   // both
+)
+
+// SpotRecommendation is a spot the user is expected to focus well at
+type SpotRecommendation struct {
+	Location    *Location
+	Score       float64 // expected productivity score
+	Sessions    int     // sessions the score rests on
+	Source      RecommendationSource
+	Explanation string
+}
+
+// TimeRecommendation is a time of the week the user focuses well at,
+// anywhere or at one type of spot
+type TimeRecommendation struct {
+	Weekday      time.Weekday
+	TimeOfDay    TimeOfDay
+	LocationType LocationType // empty for any spot
+	Score        float64
+	Sessions     int
+	Explanation  string
+}
+
+// FocusHistory groups a user's scored sessions by spot, type of spot,
+// weekday and time of day, in the user's timezone
+type FocusHistory struct {
+	groups map[historyKey]*historyGroup
+}
+
+// historyKey is a group of sessions. Zero fields group any spot, type or
+// time of day; a weekday of -1 groups any day.
+type historyKey struct {
+	locationID   primitive.ObjectID
+	locationType LocationType
+	weekday      int
+	timeOfDay    TimeOfDay
+}
+
+type historyGroup struct {
+	key        historyKey
+	sessions   int
+	totalScore float64
+}
+
+// evidence is the average score of a group and what it describes
+type evidence struct {
+	score    float64
+	sessions int
+	label    string
+}
+
+func (g *historyGroup) average() float64 {
+	return g.totalScore / float64(g.sessions)
+}
+
+// NewFocusHistory scores the active completed sessions with scorer and
+// groups them by their local start in loc
+func NewFocusHistory(sessions []*FocusSession, loc *time.Location, scorer ProductivityScorer) *FocusHistory {
+	history := &FocusHistory{groups: make(map[historyKey]*historyGroup)}
+
+	for _, session := range sessions {
+		if !session.Active || session.Status != StatusCompleted || session.ActualDuration == nil {
+			continue
+		}
+
+		score := scorer.Score(session)
+		localStart := session.StartTime.In(loc)
+		weekday := int(localStart.Weekday())
+		timeOfDay := GetTimeOfDay(localStart)
+
+		keys := []historyKey{{weekday: weekday, timeOfDay: timeOfDay}}
+		if session.LocationID != nil {
+			id := *session.LocationID
+			keys = append(keys,
+				historyKey{locationID: id, weekday: weekday, timeOfDay: timeOfDay},
+				historyKey{locationID: id, weekday: -1, timeOfDay: timeOfDay},
+				historyKey{locationID: id, weekday: -1},
+			)
+		}
+		if session.LocationDetails != nil && session.LocationDetails.Type != "" {
+			locationType := LocationType(session.LocationDetails.Type)
+			keys = append(keys,
+				historyKey{locationType: locationType, weekday: weekday, timeOfDay: timeOfDay},
+				historyKey{locationType: locationType, weekday: -1, timeOfDay: timeOfDay},
+				historyKey{locationType: locationType, weekday: -1},
+			)
+		}
+
+		for _, key := range keys {
+			group, ok := history.groups[key]
+			if !ok {
+				group = &historyGroup{key: key}
+				history.groups[key] = group
+			}
+			group.sessions++
+			group.totalScore += score
+		}
+	}
+
+	return history
+}
+
+// RecommendSpots ranks the candidate spots for a session at the planned
+// time, or at any time when at is nil. Each spot is judged by the most
+// specific group of the user's sessions that is large enough: the spot at
+// that weekday and time of day, the spot at that time of day, the spot, then
+// the same for its type of spot. Spots the user has not been to are blended
+// with the community's scores there, when given. Spots nothing is known about
+// are left out.
+func (h *FocusHistory) RecommendSpots(candidates []*Location, at *time.Time, community map[primitive.ObjectID]*LocationProductivity) []SpotRecommendation {
+	recommendations := make([]SpotRecommendation, 0, len(candidates))
+
+	for _, location := range candidates {
+		own, tried := h.spotEvidence(location, at)
+		if !tried {
+			own, _ = h.typeEvidence(location.Type, at)
+		}
+
+		var others *evidence
+		if productivity, ok := community[location.ID]; ok && !tried && productivity.Users >= MinCommunityUsers && productivity.Sessions >= MinRecommendationSessions {
+			others = &evidence{
+				score:    productivity.AverageProductivity,
+				sessions: productivity.Sessions,
+				label:    fmt.Sprintf("%d other users here", productivity.Users),
+			}
+		}
+
+		recommendation := SpotRecommendation{Location: location}
+		switch {
+		case own != nil && others != nil:
+			weight := others.sessions
+			if weight > maxCommunityWeight {
+				weight = maxCommunityWeight
+			}
+			recommendation.Score = (own.score*float64(own.sessions) + others.score*float64(weight)) / float64(own.sessions+weight)
+			recommendation.Sessions = own.sessions + others.sessions
+			recommendation.Source = SourceBlended
+			recommendation.Explanation = own.explain() + "; " + others.explain()
+		case own != nil:
+			recommendation.Score = own.score
+			recommendation.Sessions = own.sessions
+			recommendation.Source = SourceHistory
+			recommendation.Explanation = own.explain()
+		case others != nil:
+			recommendation.Score = others.score
+			recommendation.Sessions = others.sessions
+			recommendation.Source = SourceCommunity
+			recommendation.Explanation = others.explain()
+		default:
+			continue
+		}
+
+		recommendations = append(recommendations, recommendation)
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Sessions > recommendations[j].Sessions
+	})
+	return recommendations
+}
+
+// RecommendTimes returns the weekdays and times of day the user focuses best
+// at, anywhere and at each type of spot, or only at locationType when given
+func (h *FocusHistory) RecommendTimes(locationType LocationType) []TimeRecommendation {
+	var recommendations []TimeRecommendation
+
+	for key, group := range h.groups {
+		if !key.locationID.IsZero() || key.weekday < 0 || key.timeOfDay == "" {
+			continue
+		}
+		if locationType != "" && key.locationType != locationType {
+			continue
+		}
+		if group.sessions < MinRecommendationSessions {
+			continue
+		}
+
+		recommendations = append(recommendations, TimeRecommendation{
+			Weekday:      time.Weekday(key.weekday),
+			TimeOfDay:    key.timeOfDay,
+			LocationType: key.locationType,
+			Score:        group.average(),
+			Sessions:     group.sessions,
+			Explanation:  group.evidence().explain(),
+		})
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		switch {
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		case a.Sessions != b.Sessions:
+			return a.Sessions > b.Sessions
+		case a.Weekday != b.Weekday:
+			return a.Weekday < b.Weekday
+		case a.TimeOfDay != b.TimeOfDay:
+			return a.TimeOfDay < b.TimeOfDay
+		default:
+			return a.LocationType < b.LocationType
+		}
+	})
+	return recommendations
+}
+
+// spotEvidence returns the most specific large enough group of the user's
+// sessions at the spot
+func (h *FocusHistory) spotEvidence(location *Location, at *time.Time) (*evidence, bool) {
+	for _, key := range specificKeys(historyKey{locationID: location.ID}, at) {
+		if group, ok := h.groups[key]; ok && group.sessions >= MinRecommendationSessions {
+			found := group.evidence()
+			found.label = strings.Replace(found.label, "this spot", location.Name, 1)
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// typeEvidence returns the most specific large enough group of the user's
+// sessions at the type of spot
+func (h *FocusHistory) typeEvidence(locationType LocationType, at *time.Time) (*evidence, bool) {
+	for _, key := range specificKeys(historyKey{locationType: locationType}, at) {
+		if group, ok := h.groups[key]; ok && group.sessions >= MinRecommendationSessions {
+			return group.evidence(), true
+		}
+	}
+	return nil, false
+}
+
+// specificKeys returns the groups of a spot or type of spot, most specific
+// first, for a session at the planned time
+func specificKeys(base historyKey, at *time.Time) []historyKey {
+	base.weekday = -1
+	if at == nil {
+		return []historyKey{base}
+	}
+
+	timeOfDay := GetTimeOfDay(*at)
+
+	atTime := base
+	atTime.timeOfDay = timeOfDay
+
+	onDay := atTime
+	onDay.weekday = int(at.Weekday())
+
+	return []historyKey{onDay, atTime, base}
+}
+
+func (g *historyGroup) evidence() *evidence {
+	var subject string
+	switch {
+	case !g.key.locationID.IsZero():
+		subject = "this spot"
+	case g.key.locationType != "":
+		subject = g.key.locationType.plural()
+	default:
+		subject = "sessions"
+	}
+
+	label := subject
+	switch {
+	case g.key.weekday >= 0:
+		label += fmt.Sprintf(" on %s %ss", time.Weekday(g.key.weekday), g.key.timeOfDay.phrase())
+	case g.key.timeOfDay != "":
+		label += " in the " + g.key.timeOfDay.phrase()
+	}
+
+	return &evidence{
+		score:    g.average(),
+		sessions: g.sessions,
+		label:    label,
+	}
+}
+
+func (e *evidence) explain() string {
+	return fmt.Sprintf("%s: avg score %.1f over %d sessions", e.label, e.score, e.sessions)
+}
+
+// plural names the type of spot in explanations
+func (t LocationType) plural() string {
+	switch t {
+	case LocationLibrary:
+		return "libraries"
+	case LocationCoworking:
+		return "coworking spaces"
+	case LocationHome:
+		return "home"
+	case LocationOther:
+		return "other spots"
+	case LocationCafe, LocationOffice, LocationPark:
+		return string(t) + "s"
+	default:
+		return string(t)
+	}
+}
+
+// phrase names the time of day in explanations
+func (t TimeOfDay) phrase() string {
+	switch t {
+	case EarlyMorning:
+		return "early morning"
+	case LateMorning:
+		return "late morning"
+	case Afternoon:
+		return "afternoon"
+	case Evening:
+		return "evening"
+	default:
+		return "night"
+	}
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SessionSortField is a field session lists can be sorted by
+type SessionSortField string
+
+const (
+	SortByStartTime      SessionSortField = "startTime"
+	SortByEndTime        SessionSortField = "endTime"
+	SortByCreatedAt      SessionSortField = "createdAt"
+	SortByUpdatedAt      SessionSortField = "updatedAt"
+	SortByTitle          SessionSortField = "title"
+	SortByDuration       SessionSortField = "duration"
+	SortByActualDuration SessionSortField = "actualDuration"
+	SortByRating         SessionSortField = "rating"
+	SortByFocus          SessionSortField = "focus"
+)
+
+// IsValid reports whether sessions can be sorted by the field
+func (f SessionSortField) IsValid() bool {
+	switch f {
+	case SortByStartTime, SortByEndTime, SortByCreatedAt, SortByUpdatedAt, SortByTitle,
+		SortByDuration, SortByActualDuration, SortByRating, SortByFocus:
+		return true
+	}
+	return false
+}
+
+// SessionFilter narrows a user's session list. Zero values do not filter.
+// Ranges are inclusive.
+type SessionFilter struct {
+	Text         string // full-text search on title, description and notes
+	StartDate    time.Time
+	EndDate      time.Time
+	Statuses     []SessionStatus
+	Tags         []string
+	MatchAllTags bool // sessions must have every tag, not just one of them
+	LocationID   *primitive.ObjectID
+	LocationType string
+	MinRating    *int
+	MaxRating    *int
+	MinFocus     *int
+	MaxFocus     *int
+
+	// Duration bounds in minutes apply to the actual duration of finished
+	// sessions and to the planned duration of the others
+	MinDuration *int
+	MaxDuration *int
+
+	SortBy   SessionSortField // default: startTime
+	SortDesc bool
+}
+
+// SessionCursor is a position in a session list sorted by start time. Before
+// selects the page preceding the position instead of the one following it.
+type SessionCursor struct {
+	StartTime time.Time
+	ID        primitive.ObjectID
+	Before    bool
+}
+
+// SessionSearchResult is a session matching a text search with its relevance
+type SessionSearchResult struct {
+	Session *FocusSession
+	Score   float64
+}
+
+// SessionTotals add up the sessions matching a filter
+type SessionTotals struct {
+	Sessions int `bson:"sessions"`
+	Minutes  int `bson:"minutes"` // actual durations
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"focusspot/focussessionservice/utils/rrule"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SessionSeries is a template for planned sessions that repeat on an
+// RFC 5545 recurrence rule. Occurrences are materialized as FocusSessions
+// linked back through FocusSession.SeriesID.
+type SessionSeries struct {
+	ID              primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID          primitive.ObjectID  `json:"userId" bson:"userId"`
+	Title           string              `json:"title" bson:"title"`
+	Description     string              `json:"description" bson:"description"`
+	RRule           string              `json:"rrule" bson:"rrule"`
+	StartTime       time.Time           `json:"startTime" bson:"startTime"` // first occurrence (DTSTART)
+	Timezone        string              `json:"timezone" bson:"timezone"`   // IANA name the rule is expanded in
+	Until           *time.Time          `json:"until,omitempty" bson:"until,omitempty"`
+	Duration        int                 `json:"duration" bson:"duration"` // minutes
+	LocationID      *primitive.ObjectID `json:"locationId,omitempty" bson:"locationId,omitempty"`
+	LocationDetails *LocationDetails    `json:"locationDetails,omitempty" bson:"locationDetails,omitempty"`
+	Tags            []string            `json:"tags,omitempty" bson:"tags"`
+	ExceptionDates  []time.Time         `json:"exceptionDates,omitempty" bson:"exceptionDates,omitempty"` // cancelled occurrences (EXDATE)
+	CreatedAt       time.Time           `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt" bson:"updatedAt"`
+	Active          bool                `json:"active" bson:"active"` // default: true
+}
+
+type SeriesScope string
+
+const (
+	ScopeThis      SeriesScope = "this"
+	ScopeFollowing SeriesScope = "following"
+	ScopeAll       SeriesScope = "all"
+)
+
+// Location returns the timezone the series is expanded in
+func (s *SessionSeries) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// Occurrences returns the start times of the series in [from, to), leaving
+// out cancelled occurrences and anything after Until
+func (s *SessionSeries) Occurrences(from, to time.Time) ([]time.Time, error) {
+	rule, err := rrule.Parse(s.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Until != nil && s.Until.Before(to) {
+		to = s.Until.Add(time.Second)
+	}
+
+	var occurrences []time.Time
+	for _, t := range rule.Between(s.StartTime.In(loc), from, to) {
+		if !s.IsException(t) {
+			occurrences = append(occurrences, t)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// HasOccurrence reports whether t is a scheduled occurrence of the series
+func (s *SessionSeries) HasOccurrence(t time.Time) (bool, error) {
+	occurrences, err := s.Occurrences(t, t.Add(time.Second))
+	if err != nil {
+		return false, err
+	}
+	return len(occurrences) > 0, nil
+}
+
+// IsException reports whether the occurrence at t was cancelled
+func (s *SessionSeries) IsException(t time.Time) bool {
+	for _, exception := range s.ExceptionDates {
+		if exception.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewOccurrence builds the planned session for the occurrence starting at t
+func (s *SessionSeries) NewOccurrence(t time.Time) *FocusSession {
+	now := time.Now()
+	occurrence := t
+
+	return &FocusSession{
+		ID:              primitive.NewObjectID(),
+		UserID:          s.UserID,
+		Title:           s.Title,
+		Description:     s.Description,
+		StartTime:       t,
+		Duration:        s.Duration,
+		Status:          StatusPlanned,
+		SeriesID:        &s.ID,
+		OccurrenceTime:  &occurrence,
+		LocationID:      s.LocationID,
+		LocationDetails: s.LocationDetails,
+		Tags:            s.Tags,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Active:          true,
+	}
+}
@@ -0,0 +1,223 @@
+package entity
+
+import (
+	"math"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MinCorrelationSpots is how many spots a correlation needs before it is
+// worth reporting. Every session at a spot shares its rating, so spots rather
+// than sessions are the samples.
+const MinCorrelationSpots = 4
+
+// Correlation is Pearson's r between the spots' average rating and the
+// user's average focus or distractions at each spot. Coefficient is nil with
+// fewer than MinCorrelationSpots spots or when either side never varies; the
+// p-value is that of a two-sided t-test of r over the spots, and is 1 when
+// there is no coefficient.
+type Correlation struct {
+	Coefficient *float64
+	PValue      float64
+	Spots       int
+	Sessions    int
+}
+
+// RatingCorrelation is how a spot's average rating of one aspect goes with
+// the user's focus and distractions in sessions there
+type RatingCorrelation struct {
+	Aspect       ReviewAspect
+	Focus        Correlation
+	Distractions Correlation
+}
+
+// SpotFit is how the user focuses at a rated spot
+type SpotFit struct {
+	Ratings             *LocationRatings
+	Sessions            int // completed sessions with focus or distractions recorded
+	AverageFocus        *float64
+	AverageDistractions *float64
+}
+
+// ComputeSpotInsights pairs the user's completed sessions at rated spots
+// with the spots' ratings. It returns how each aspect correlates with focus
+// and distractions, and the spots the user recorded either at, best focus
+// first.
+func ComputeSpotInsights(sessions []*FocusSession, ratings []*LocationRatings) ([]RatingCorrelation, []SpotFit) {
+	byLocation := make(map[primitive.ObjectID]*LocationRatings, len(ratings))
+	for _, r := range ratings {
+		byLocation[r.LocationID] = r
+	}
+
+	type spotTotals struct {
+		sessions                      int
+		focus, distractions           float64
+		focusCount, distractionsCount int
+	}
+	totals := make(map[*LocationRatings]*spotTotals)
+
+	focusPairs := make(map[ReviewAspect]*spotPairs, len(ReviewAspects))
+	distractionPairs := make(map[ReviewAspect]*spotPairs, len(ReviewAspects))
+	for _, aspect := range ReviewAspects {
+		focusPairs[aspect] = &spotPairs{}
+		distractionPairs[aspect] = &spotPairs{}
+	}
+
+	for _, session := range sessions {
+		if !session.Active || session.Status != StatusCompleted || session.LocationID == nil {
+			continue
+		}
+		if session.Focus == nil && session.Distractions == nil {
+			continue
+		}
+
+		spot, ok := byLocation[*session.LocationID]
+		if !ok {
+			continue
+		}
+
+		spotTotal, ok := totals[spot]
+		if !ok {
+			spotTotal = &spotTotals{}
+			totals[spot] = spotTotal
+		}
+		spotTotal.sessions++
+
+		if session.Focus != nil {
+			spotTotal.focus += float64(*session.Focus)
+			spotTotal.focusCount++
+		}
+		if session.Distractions != nil {
+			spotTotal.distractions += float64(*session.Distractions)
+			spotTotal.distractionsCount++
+		}
+
+	}
+
+	fits := make([]SpotFit, 0, len(totals))
+	for spot, spotTotal := range totals {
+		fit := SpotFit{Ratings: spot, Sessions: spotTotal.sessions}
+		if spotTotal.focusCount > 0 {
+			average := spotTotal.focus / float64(spotTotal.focusCount)
+			fit.AverageFocus = &average
+		}
+		if spotTotal.distractionsCount > 0 {
+			average := spotTotal.distractions / float64(spotTotal.distractionsCount)
+			fit.AverageDistractions = &average
+		}
+		fits = append(fits, fit)
+
+		for _, aspect := range ReviewAspects {
+			rating := spot.Average(aspect)
+			if rating == nil {
+				continue
+			}
+			if fit.AverageFocus != nil {
+				focusPairs[aspect].add(*rating, *fit.AverageFocus, spotTotal.focusCount)
+			}
+			if fit.AverageDistractions != nil {
+				distractionPairs[aspect].add(*rating, *fit.AverageDistractions, spotTotal.distractionsCount)
+			}
+		}
+	}
+
+	correlations := make([]RatingCorrelation, 0, len(ReviewAspects))
+	for _, aspect := range ReviewAspects {
+		correlations = append(correlations, RatingCorrelation{
+			Aspect:       aspect,
+			Focus:        focusPairs[aspect].correlate(),
+			Distractions: distractionPairs[aspect].correlate(),
+		})
+	}
+
+	sort.Slice(fits, func(i, j int) bool {
+		a, b := fits[i], fits[j]
+		switch {
+		case !sameAverage(a.AverageFocus, b.AverageFocus):
+			return higherAverage(a.AverageFocus, b.AverageFocus)
+		case !sameAverage(a.AverageDistractions, b.AverageDistractions):
+			return lowerAverage(a.AverageDistractions, b.AverageDistractions)
+		case a.Sessions != b.Sessions:
+			return a.Sessions > b.Sessions
+		default:
+			return a.Ratings.LocationID.Hex() < b.Ratings.LocationID.Hex()
+		}
+	})
+
+	return correlations, fits
+}
+
+// spotPairs are the rating of each spot and the user's average measure there
+type spotPairs struct {
+	xs, ys   []float64
+	sessions int
+}
+
+func (p *spotPairs) add(rating, average float64, sessions int) {
+	p.xs = append(p.xs, rating)
+	p.ys = append(p.ys, average)
+	p.sessions += sessions
+}
+
+// correlate computes Pearson's r of the pairs and its significance
+func (p *spotPairs) correlate() Correlation {
+	correlation := Correlation{PValue: 1, Spots: len(p.xs), Sessions: p.sessions}
+	if correlation.Spots < MinCorrelationSpots {
+		return correlation
+	}
+
+	xs, ys := p.xs, p.ys
+	n := float64(correlation.Spots)
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var sxx, syy, sxy float64
+	for i := range xs {
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+		syy += (ys[i] - meanY) * (ys[i] - meanY)
+		sxy += (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	if sxx == 0 || syy == 0 {
+		return correlation
+	}
+
+	r := sxy / math.Sqrt(sxx*syy)
+	correlation.Coefficient = &r
+
+	df := n - 2
+	if r*r < 1 {
+		correlation.PValue = studentTwoSidedP(r*math.Sqrt(df/(1-r*r)), df)
+	} else {
+		correlation.PValue = 0
+	}
+	return correlation
+}
+
+func sameAverage(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// higherAverage reports whether a is above b, ranking a missing average last
+func higherAverage(a, b *float64) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || *a > *b
+}
+
+// lowerAverage reports whether a is below b, ranking a missing average last
+func lowerAverage(a, b *float64) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || *a < *b
+}
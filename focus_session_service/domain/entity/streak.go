@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StreakDateLayout is the format of streak days, which are calendar days in
+// the user's timezone
+const StreakDateLayout = "2006-01-02"
+
+// DefaultStreakThreshold is the focus minutes a day needs by default
+const DefaultStreakThreshold = 25
+
+// StreakSettings decide which days count. A day qualifies when its completed
+// sessions add up to ThresholdMinutes. Rest days never break a streak, and
+// count towards it when they qualify anyway.
+type StreakSettings struct {
+	ThresholdMinutes int            `json:"thresholdMinutes" bson:"thresholdMinutes"`
+	RestDays         []time.Weekday `json:"restDays,omitempty" bson:"restDays,omitempty"`
+	Timezone         string         `json:"timezone,omitempty" bson:"timezone,omitempty"` // IANA name; empty follows the user's preference
+}
+
+// Streak is a user's streak state as of LastQualified. Days are
+// StreakDateLayout strings.
+type Streak struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `json:"userId" bson:"userId"`
+	Settings      StreakSettings     `json:"settings" bson:"settings"`
+	Timezone      string             `json:"timezone" bson:"timezone"` // the zone the days below are in
+	Current       int                `json:"current" bson:"current"`
+	CurrentStart  string             `json:"currentStart,omitempty" bson:"currentStart,omitempty"`
+	Longest       int                `json:"longest" bson:"longest"`
+	LongestStart  string             `json:"longestStart,omitempty" bson:"longestStart,omitempty"`
+	LongestEnd    string             `json:"longestEnd,omitempty" bson:"longestEnd,omitempty"`
+	LastQualified string             `json:"lastQualified,omitempty" bson:"lastQualified,omitempty"`
+	Stale         bool               `json:"stale" bson:"stale"` // history changed; recompute before use
+	UpdatedAt     time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+func DefaultStreakSettings() StreakSettings {
+	return StreakSettings{
+		ThresholdMinutes: DefaultStreakThreshold,
+	}
+}
+
+// StreakDay returns the streak day a moment falls on in loc
+func StreakDay(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(StreakDateLayout)
+}
+
+func (s StreakSettings) IsRestDay(day time.Weekday) bool {
+	for _, rest := range s.RestDays {
+		if rest == day {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyRestDaysBetween reports whether every day strictly between from and to
+// is a rest day
+func (s StreakSettings) onlyRestDaysBetween(from, to string) bool {
+	start, err := time.Parse(StreakDateLayout, from)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(StreakDateLayout, to)
+	if err != nil {
+		return false
+	}
+
+	for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !s.IsRestDay(day.Weekday()) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddQualifiedDay extends the streak with a day that reached the threshold.
+// A day before LastQualified cannot be added incrementally and marks the
+// streak stale instead.
+func (st *Streak) AddQualifiedDay(day string) {
+	switch {
+	case st.LastQualified == "":
+		st.Current = 1
+		st.CurrentStart = day
+	case day == st.LastQualified:
+		return
+	case day < st.LastQualified:
+		st.Stale = true
+		return
+	case st.Settings.onlyRestDaysBetween(st.LastQualified, day):
+		st.Current++
+	default:
+		st.Current = 1
+		st.CurrentStart = day
+	}
+
+	st.LastQualified = day
+	if st.Current > st.Longest {
+		st.Longest = st.Current
+		st.LongestStart = st.CurrentStart
+		st.LongestEnd = day
+	}
+}
+
+// CurrentAt returns the streak still alive on the given day. Today not
+// having qualified yet does not break it.
+func (st *Streak) CurrentAt(today string) int {
+	if st.LastQualified == "" || st.LastQualified > today {
+		return st.Current
+	}
+	if st.LastQualified == today || st.Settings.onlyRestDaysBetween(st.LastQualified, today) {
+		return st.Current
+	}
+	return 0
+}
+
+// Recompute rebuilds the streak from the focus minutes of every day
+func (st *Streak) Recompute(dailyMinutes map[string]int) {
+	st.Current, st.Longest = 0, 0
+	st.CurrentStart, st.LongestStart, st.LongestEnd, st.LastQualified = "", "", "", ""
+	st.Stale = false
+
+	days := make([]string, 0, len(dailyMinutes))
+	for day, minutes := range dailyMinutes {
+		if minutes >= st.Settings.ThresholdMinutes {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+
+	for _, day := range days {
+		st.AddQualifiedDay(day)
+	}
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tag holds the settings of one of a user's tags. Sessions store tags by
+// name, so a tag can be in use without a Tag document.
+type Tag struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	Name      string             `json:"name" bson:"name"`
+	Color     string             `json:"color,omitempty" bson:"color,omitempty"` // #RRGGBB
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// TagUsage summarizes the sessions carrying a tag
+type TagUsage struct {
+	Name     string    `json:"name" bson:"_id"`
+	Sessions int       `json:"sessions" bson:"sessions"`
+	Minutes  int       `json:"minutes" bson:"minutes"` // actual duration of completed sessions
+	LastUsed time.Time `json:"lastUsed" bson:"lastUsed"`
+}
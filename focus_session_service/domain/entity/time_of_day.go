@@ -12,7 +12,8 @@ const (
     Night TimeOfDay = "night" // 21:00-4:59
 )
 
-// GetTimeOfDay returns the time of day for a given time
+// GetTimeOfDay returns the time of day for a given time, on the clock of
+// t's location. Convert t to the user's timezone first.
 func GetTimeOfDay(t time.Time) TimeOfDay {
     hour := t.Hour()
 
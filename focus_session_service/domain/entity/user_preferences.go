@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserPreferences are per-user settings of this service
+type UserPreferences struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	Timezone  string             `json:"timezone,omitempty" bson:"timezone,omitempty"` // IANA name, used when a request names none
+	Scorer    string             `json:"scorer,omitempty" bson:"scorer,omitempty"`     // productivity scorer name, default classic
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
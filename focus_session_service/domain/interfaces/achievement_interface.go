@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IAchievementRepository interface {
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.Achievement, error)
+	// Unlock records an achievement once; unlocking it again changes nothing
+	Unlock(ctx context.Context, achievement *entity.Achievement) error
+}
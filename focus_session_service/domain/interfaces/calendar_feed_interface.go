@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ICalendarFeedRepository interface {
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.CalendarFeed, error)
+	GetByToken(ctx context.Context, token string) (*entity.CalendarFeed, error)
+	Upsert(ctx context.Context, feed *entity.CalendarFeed) error
+	Delete(ctx context.Context, userID primitive.ObjectID) error
+}
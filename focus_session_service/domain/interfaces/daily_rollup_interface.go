@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IDailyRollupRepository interface {
+	// GetTimezone returns the timezone of the user's rollups, or an empty
+	// string when the user has none
+	GetTimezone(ctx context.Context, userID primitive.ObjectID) (string, error)
+
+	// GetRange returns the rollups of the days from fromDay to toDay
+	// (YYYY-MM-DD, inclusive), ordered by day
+	GetRange(ctx context.Context, userID primitive.ObjectID, fromDay, toDay string) ([]*entity.DailyRollup, error)
+
+	// Add adds a rollup to the stored one of its day, creating it if needed.
+	// A day left without sessions is removed.
+	Add(ctx context.Context, rollup *entity.DailyRollup) error
+
+	// ReplaceUser replaces all of a user's rollups
+	ReplaceUser(ctx context.Context, userID primitive.ObjectID, rollups []*entity.DailyRollup) error
+}
@@ -11,13 +11,32 @@ import (
 type IFocusSessionRepository interface {
 	Create(ctx context.Context, session *entity.FocusSession) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*entity.FocusSession, error)
-	GetByUserID(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*entity.FocusSession, error)
+	GetByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter, limit, offset int) ([]*entity.FocusSession, error)
+	GetPageByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter, cursor *entity.SessionCursor, limit int) ([]*entity.FocusSession, error)
+	SearchByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter, limit, offset int) ([]*entity.SessionSearchResult, error)
+	CountByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter) (int64, error)
+	GetTotals(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter) (*entity.SessionTotals, error)
+	GetByExternalUID(ctx context.Context, userID primitive.ObjectID, externalUID string) (*entity.FocusSession, error)
 	GetActiveByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.FocusSession, error)
 	GetSessionsByDateRange(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) ([]*entity.FocusSession, error)
+	UpsertOccurrence(ctx context.Context, session *entity.FocusSession) error
+	ForEachSession(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time, fn func(*entity.FocusSession) error) error
+	GetBySeriesID(ctx context.Context, seriesID primitive.ObjectID, from, to time.Time) ([]*entity.FocusSession, error)
+	MoveOccurrences(ctx context.Context, seriesID, newSeriesID primitive.ObjectID, from time.Time) error
+	CancelPlannedOccurrences(ctx context.Context, seriesID primitive.ObjectID, from time.Time) error
+	GetUserIDs(ctx context.Context) ([]primitive.ObjectID, error)
+	GetTagUsage(ctx context.Context, userID primitive.ObjectID) ([]*entity.TagUsage, error)
+	ReplaceTag(ctx context.Context, userID primitive.ObjectID, from, to string) (int64, error)
+	RemoveTag(ctx context.Context, userID primitive.ObjectID, name string) (int64, error)
 	Update(ctx context.Context, sesion *entity.FocusSession) error
 	UpdateStatus(ctx context.Context, id primitive.ObjectID, status entity.SessionStatus) error
+	StartSession(ctx context.Context, id primitive.ObjectID, startTime time.Time) error
+	PauseSession(ctx context.Context, id primitive.ObjectID, pausedAt time.Time) error
+	ResumeSession(ctx context.Context, id primitive.ObjectID, resumedAt time.Time) error
 	EndSession(ctx context.Context, id primitive.ObjectID, endTime time.Time, notes string, rating, focus, energy, mood, distractions *int) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
-	GetProductivityStats(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time) (*entity.ProductivityStats, error)
-	GetProductivityTrends(ctx context.Context, userID primitive.ObjectID, period entity.Period) (*entity.ProductivityTrends, error)
+	GetProductivityStats(ctx context.Context, userID primitive.ObjectID, startDate, endDate time.Time, loc *time.Location, scorer entity.ProductivityScorer) (*entity.ProductivityStats, error)
+	GetProductivityTrends(ctx context.Context, userID primitive.ObjectID, period entity.Period, startDate, endDate time.Time, scorer entity.ProductivityScorer) (*entity.ProductivityTrends, error)
+	GetLocationProductivity(ctx context.Context, userID primitive.ObjectID, locationIDs []primitive.ObjectID, scorer entity.ProductivityScorer) ([]*entity.LocationProductivity, error)
+	GetCommunityLocationProductivity(ctx context.Context, excludeUserID primitive.ObjectID, locationIDs []primitive.ObjectID, scorer entity.ProductivityScorer) ([]*entity.LocationProductivity, error)
 }
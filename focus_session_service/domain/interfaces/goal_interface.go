@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IGoalRepository interface {
+	Create(ctx context.Context, goal *entity.Goal) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*entity.Goal, error)
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.Goal, error)
+	Update(ctx context.Context, goal *entity.Goal) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ILocationRepository interface {
+	Create(ctx context.Context, location *entity.Location) error
+	GetByID(ctx context.Context, id, viewerID primitive.ObjectID) (*entity.Location, error)
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID, viewerID primitive.ObjectID) ([]*entity.Location, error)
+	List(ctx context.Context, filter entity.LocationFilter, limit, offset int) ([]*entity.Location, error)
+	GetNearby(ctx context.Context, point entity.GeoPoint, radius float64, filter entity.LocationFilter, limit int) ([]*entity.NearbyLocation, error)
+	Update(ctx context.Context, location *entity.Location) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
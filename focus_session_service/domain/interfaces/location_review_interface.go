@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ILocationReviewRepository interface {
+	GetByUser(ctx context.Context, locationID, userID primitive.ObjectID) (*entity.LocationReview, error)
+	ListByLocation(ctx context.Context, locationID primitive.ObjectID, limit, offset int) ([]*entity.LocationReview, error)
+	Upsert(ctx context.Context, review *entity.LocationReview) error
+	Delete(ctx context.Context, locationID, userID primitive.ObjectID) error
+	GetRatings(ctx context.Context, locationIDs []primitive.ObjectID) ([]*entity.LocationRatings, error)
+}
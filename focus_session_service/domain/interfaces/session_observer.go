@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ISessionObserver is told about each session change once it is saved.
+// Before is nil for new sessions and after is nil for deleted ones.
+// Observers handle their own errors; they cannot fail the change.
+type ISessionObserver interface {
+	OnSessionChanged(ctx context.Context, before, after *entity.FocusSession)
+
+	// OnSessionsImported is told once about all the sessions an import
+	// created for a user, in place of a change per session
+	OnSessionsImported(ctx context.Context, userID primitive.ObjectID, sessions []*entity.FocusSession)
+}
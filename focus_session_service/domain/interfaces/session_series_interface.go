@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ISessionSeriesRepository interface {
+	Create(ctx context.Context, series *entity.SessionSeries) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*entity.SessionSeries, error)
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.SessionSeries, error)
+	Update(ctx context.Context, series *entity.SessionSeries) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	ReplaceTag(ctx context.Context, userID primitive.ObjectID, from, to string) error
+	RemoveTag(ctx context.Context, userID primitive.ObjectID, name string) error
+}
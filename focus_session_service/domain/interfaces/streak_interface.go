@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IStreakRepository interface {
+	// GetByUserID returns nil and no error when the user has no streak yet
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.Streak, error)
+	Save(ctx context.Context, streak *entity.Streak) error
+	MarkStale(ctx context.Context, userID primitive.ObjectID) error
+}
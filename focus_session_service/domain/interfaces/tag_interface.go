@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ITagRepository interface {
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.Tag, error)
+	GetByName(ctx context.Context, userID primitive.ObjectID, name string) (*entity.Tag, error)
+	Upsert(ctx context.Context, tag *entity.Tag) error
+	Rename(ctx context.Context, userID primitive.ObjectID, from, to string) error
+	Delete(ctx context.Context, userID primitive.ObjectID, names []string) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IUserPreferencesRepository interface {
+	// GetByUserID returns nil and no error when the user has no preferences
+	GetByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.UserPreferences, error)
+	Save(ctx context.Context, preferences *entity.UserPreferences) error
+}
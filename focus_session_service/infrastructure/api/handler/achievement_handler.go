@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"focusspot/focussessionservice/application/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AchievementHandler struct {
+	achievementUseCase usecase.IAchievementUseCase
+}
+
+func NewAchievementHandler(achievementUseCase usecase.IAchievementUseCase) *AchievementHandler {
+	return &AchievementHandler{
+		achievementUseCase: achievementUseCase,
+	}
+}
+
+func (h *AchievementHandler) GetAchievements(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	achievements, err := h.achievementUseCase.GetAchievements(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(achievements)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CalendarHandler struct {
+	calendarUseCase usecase.ICalendarUseCase
+}
+
+func NewCalendarHandler(calendarUseCase usecase.ICalendarUseCase) *CalendarHandler {
+	return &CalendarHandler{
+		calendarUseCase: calendarUseCase,
+	}
+}
+
+func (h *CalendarHandler) ExportCalendar(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	req := dto.CalendarExportRequest{
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Timezone: c.Query("timezone"),
+	}
+
+	calendar, err := h.calendarUseCase.ExportCalendar(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="focus-sessions.ics"`)
+	return c.Status(fiber.StatusOK).Send(calendar)
+}
+
+// GetCalendarFeed serves the public subscription feed identified by its token
+func (h *CalendarHandler) GetCalendarFeed(c *fiber.Ctx) error {
+	token := strings.TrimSuffix(c.Params("token"), ".ics")
+
+	req := dto.CalendarExportRequest{
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Timezone: c.Query("timezone"),
+	}
+
+	calendar, err := h.calendarUseCase.ExportCalendarByToken(c.Context(), token, req)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.Status(fiber.StatusOK).Send(calendar)
+}
+
+func (h *CalendarHandler) GetSubscription(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	subscription, err := h.calendarUseCase.GetSubscription(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	subscription.URL = c.BaseURL() + subscription.Path
+	return c.Status(fiber.StatusOK).JSON(subscription)
+}
+
+func (h *CalendarHandler) CreateSubscription(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	subscription, err := h.calendarUseCase.CreateSubscription(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	subscription.URL = c.BaseURL() + subscription.Path
+	return c.Status(fiber.StatusCreated).JSON(subscription)
+}
+
+func (h *CalendarHandler) DeleteSubscription(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	err := h.calendarUseCase.DeleteSubscription(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Calendar subscription deleted successfully",
+	})
+}
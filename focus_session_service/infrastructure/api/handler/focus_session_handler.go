@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"focusspot/focussessionservice/application/dto"
 	"focusspot/focussessionservice/application/usecases"
 	"focusspot/focussessionservice/domain/entity"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -57,11 +60,11 @@ func (h *FocusSessionHandler) GetSessionByID(c *fiber.Ctx) error {
 func (h *FocusSessionHandler) GetUserSessions(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 
-	req := dto.GetSessionsRequest{
-		StartDate: c.Query("startDate"),
-		EndDate:   c.Query("endDate"),
-		Limit:     c.QueryInt("limit", 20),
-		Offset:    c.QueryInt("offset", 0),
+	req, err := parseGetSessionsRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
 	sessions, err := h.sessionUseCase.GetUserSessions(c.Context(), userID, req)
@@ -74,6 +77,33 @@ func (h *FocusSessionHandler) GetUserSessions(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(sessions)
 }
 
+// SearchSessions runs a text search over titles, descriptions and notes. It
+// takes the same filters as GetUserSessions.
+func (h *FocusSessionHandler) SearchSessions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	filters, err := parseGetSessionsRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	req := dto.SearchSessionsRequest{
+		Query:              c.Query("q"),
+		GetSessionsRequest: filters,
+	}
+
+	results, err := h.sessionUseCase.SearchSessions(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(results)
+}
+
 func (h *FocusSessionHandler) GetActiveSession(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 
@@ -122,6 +152,34 @@ func (h *FocusSessionHandler) StartSession(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(session)
 }
 
+func (h *FocusSessionHandler) PauseSession(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	sessionID := c.Params("id")
+
+	session, err := h.sessionUseCase.PauseSession(c.Context(), sessionID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(session)
+}
+
+func (h *FocusSessionHandler) ResumeSession(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	sessionID := c.Params("id")
+
+	session, err := h.sessionUseCase.ResumeSession(c.Context(), sessionID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(session)
+}
+
 func (h *FocusSessionHandler) EndSession(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 	sessionID := c.Params("id")
@@ -179,6 +237,7 @@ func (h *FocusSessionHandler) GetProductivityStats(c *fiber.Ctx) error {
 	req := dto.GetProductivityStatsRequest{
 		StartDate: c.Query("startDate"),
 		EndDate:   c.Query("endDate"),
+		Timezone:  c.Query("timezone"),
 	}
 
 	stats, err := h.sessionUseCase.GetProductivityStats(c.Context(), userID, req)
@@ -195,8 +254,12 @@ func (h *FocusSessionHandler) GetProductivityTrends(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 
 	req := dto.GetProductivityTrendsRequest{
-		Period: entity.Period(entity.Weekly),
-		Limit:  c.QueryInt("limit", 12),
+		Period:   entity.Period(c.Query("period", string(entity.Weekly))),
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		Limit:    c.QueryInt("limit", 0),
+		Compare:  c.QueryBool("compare", false),
+		Timezone: c.Query("timezone"),
 	}
 
 	trends, err := h.sessionUseCase.GetProductivityTrends(c.Context(), userID, req)
@@ -208,3 +271,86 @@ func (h *FocusSessionHandler) GetProductivityTrends(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(trends)
 }
+
+func (h *FocusSessionHandler) GetProductivityHeatmap(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	req := dto.GetProductivityHeatmapRequest{
+		Year:     c.QueryInt("year", 0),
+		Timezone: c.Query("timezone"),
+	}
+
+	heatmap, err := h.sessionUseCase.GetProductivityHeatmap(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(heatmap)
+}
+
+// parseGetSessionsRequest reads the session list filters from the query string
+func parseGetSessionsRequest(c *fiber.Ctx) (dto.GetSessionsRequest, error) {
+	req := dto.GetSessionsRequest{
+		StartDate:    c.Query("startDate"),
+		EndDate:      c.Query("endDate"),
+		Status:       queryList(c, "status"),
+		Tags:         queryList(c, "tags"),
+		TagMatch:     c.Query("tagMatch"),
+		LocationID:   c.Query("locationId"),
+		LocationType: c.Query("locationType"),
+		Sort:         c.Query("sort"),
+		Order:        c.Query("order"),
+		Cursor:       c.Query("cursor"),
+		Timezone:     c.Query("timezone"),
+		Limit:        c.QueryInt("limit", 20),
+		Offset:       c.QueryInt("offset", 0),
+	}
+
+	ranges := []struct {
+		key   string
+		value **int
+	}{
+		{"minRating", &req.MinRating},
+		{"maxRating", &req.MaxRating},
+		{"minFocus", &req.MinFocus},
+		{"maxFocus", &req.MaxFocus},
+		{"minDuration", &req.MinDuration},
+		{"maxDuration", &req.MaxDuration},
+	}
+	for _, r := range ranges {
+		value, err := queryOptionalInt(c, r.key)
+		if err != nil {
+			return req, err
+		}
+		*r.value = value
+	}
+
+	return req, nil
+}
+
+// queryList splits a comma separated query parameter, dropping empty items
+func queryList(c *fiber.Ctx, key string) []string {
+	var items []string
+	for _, item := range strings.Split(c.Query(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// queryOptionalInt returns nil when the query parameter is absent
+func queryOptionalInt(c *fiber.Ctx, key string) (*int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &value, nil
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type GoalHandler struct {
+	goalUseCase usecase.IGoalUseCase
+}
+
+func NewGoalHandler(goalUseCase usecase.IGoalUseCase) *GoalHandler {
+	return &GoalHandler{
+		goalUseCase: goalUseCase,
+	}
+}
+
+func (h *GoalHandler) CreateGoal(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.CreateGoalRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	goal, err := h.goalUseCase.CreateGoal(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(goal)
+}
+
+func (h *GoalHandler) GetUserGoals(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	goals, err := h.goalUseCase.GetUserGoals(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(goals)
+}
+
+func (h *GoalHandler) GetGoalByID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	goalID := c.Params("id")
+
+	goal, err := h.goalUseCase.GetGoalByID(c.Context(), goalID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(goal)
+}
+
+func (h *GoalHandler) UpdateGoal(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	goalID := c.Params("id")
+
+	var req dto.UpdateGoalRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	goal, err := h.goalUseCase.UpdateGoal(c.Context(), goalID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(goal)
+}
+
+func (h *GoalHandler) DeleteGoal(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	goalID := c.Params("id")
+
+	if err := h.goalUseCase.DeleteGoal(c.Context(), goalID, userID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Goal deleted successfully",
+	})
+}
+
+// GetGoalProgress returns a goal's progress in the period containing ?date=
+// (default today)
+func (h *GoalHandler) GetGoalProgress(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	goalID := c.Params("id")
+
+	req := dto.GetGoalProgressRequest{
+		Date:     c.Query("date"),
+		Timezone: c.Query("timezone"),
+	}
+
+	progress, err := h.goalUseCase.GetGoalProgress(c.Context(), goalID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(progress)
+}
+
+// GetAllGoalProgress returns the progress of every goal
+func (h *GoalHandler) GetAllGoalProgress(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	req := dto.GetGoalProgressRequest{
+		Date:     c.Query("date"),
+		Timezone: c.Query("timezone"),
+	}
+
+	progress, err := h.goalUseCase.GetAllGoalProgress(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(progress)
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"fmt"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LocationHandler struct {
+	locationUseCase usecase.ILocationUseCase
+}
+
+func NewLocationHandler(locationUseCase usecase.ILocationUseCase) *LocationHandler {
+	return &LocationHandler{
+		locationUseCase: locationUseCase,
+	}
+}
+
+func (h *LocationHandler) CreateLocation(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.CreateLocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	location, err := h.locationUseCase.CreateLocation(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(location)
+}
+
+// GetLocations lists spots by ?type=, ?q= (part of the name), ?amenities=
+// and ?mine=true, a page of ?limit= at ?offset=
+func (h *LocationHandler) GetLocations(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	req := dto.GetLocationsRequest{
+		Type:      c.Query("type"),
+		Query:     c.Query("q"),
+		Amenities: queryList(c, "amenities"),
+		Mine:      c.QueryBool("mine", false),
+		Limit:     c.QueryInt("limit", 0),
+		Offset:    c.QueryInt("offset", 0),
+	}
+
+	locations, err := h.locationUseCase.GetLocations(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(locations)
+}
+
+// GetNearbyLocations lists the spots within ?radius= meters of ?lat= and
+// ?lng=, nearest first, optionally only those of ?type=
+func (h *LocationHandler) GetNearbyLocations(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	latitude, err := queryOptionalFloat(c, "lat")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	longitude, err := queryOptionalFloat(c, "lng")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	radius, err := queryOptionalFloat(c, "radius")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	req := dto.GetNearbyLocationsRequest{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Type:      c.Query("type"),
+		Limit:     c.QueryInt("limit", 0),
+	}
+	if radius != nil {
+		req.Radius = *radius
+	}
+
+	locations, err := h.locationUseCase.GetNearbyLocations(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(locations)
+}
+
+// GetRecommendations ranks spots for a session starting at ?at=, the user's
+// spots and those within ?radius= meters of ?lat= and ?lng=, and lists the
+// best times of the week to focus. ?community=true blends in other users'
+// scores for spots the user has not tried.
+func (h *LocationHandler) GetRecommendations(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	latitude, err := queryOptionalFloat(c, "lat")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	longitude, err := queryOptionalFloat(c, "lng")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	radius, err := queryOptionalFloat(c, "radius")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	req := dto.GetRecommendationsRequest{
+		At:        c.Query("at"),
+		Latitude:  latitude,
+		Longitude: longitude,
+		Type:      c.Query("type"),
+		Limit:     c.QueryInt("limit", 0),
+		Community: c.QueryBool("community", false),
+		Timezone:  c.Query("timezone"),
+	}
+	if radius != nil {
+		req.Radius = *radius
+	}
+
+	recommendations, err := h.locationUseCase.GetRecommendations(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(recommendations)
+}
+
+func (h *LocationHandler) GetLocationByID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	locationID := c.Params("id")
+
+	location, err := h.locationUseCase.GetLocationByID(c.Context(), locationID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(location)
+}
+
+func (h *LocationHandler) UpdateLocation(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	locationID := c.Params("id")
+
+	var req dto.UpdateLocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	location, err := h.locationUseCase.UpdateLocation(c.Context(), locationID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(location)
+}
+
+func (h *LocationHandler) DeleteLocation(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	locationID := c.Params("id")
+
+	if err := h.locationUseCase.DeleteLocation(c.Context(), locationID, userID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Location deleted successfully",
+	})
+}
+
+// queryOptionalFloat returns nil when the query parameter is absent
+func queryOptionalFloat(c *fiber.Ctx, key string) (*float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &value, nil
+}
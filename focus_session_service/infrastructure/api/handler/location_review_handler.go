@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LocationReviewHandler struct {
+	reviewUseCase usecase.ILocationReviewUseCase
+}
+
+func NewLocationReviewHandler(reviewUseCase usecase.ILocationReviewUseCase) *LocationReviewHandler {
+	return &LocationReviewHandler{
+		reviewUseCase: reviewUseCase,
+	}
+}
+
+// ReviewLocation creates or replaces the user's review of the spot
+func (h *LocationReviewHandler) ReviewLocation(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	locationID := c.Params("id")
+
+	var req dto.ReviewLocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	review, err := h.reviewUseCase.ReviewLocation(c.Context(), locationID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(review)
+}
+
+func (h *LocationReviewHandler) DeleteReview(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	locationID := c.Params("id")
+
+	if err := h.reviewUseCase.DeleteReview(c.Context(), locationID, userID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Review deleted successfully",
+	})
+}
+
+// GetLocationReviews returns the spot's average ratings and a page of its
+// reviews, ?limit= at ?offset=
+func (h *LocationReviewHandler) GetLocationReviews(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	locationID := c.Params("id")
+
+	req := dto.GetLocationReviewsRequest{
+		Limit:  c.QueryInt("limit", 0),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	reviews, err := h.reviewUseCase.GetLocationReviews(c.Context(), locationID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+// GetSpotInsights correlates spot ratings with the user's focus and
+// distractions, and lists the spots they focus best at. ?noise=, ?wifi=,
+// ?outlets=, ?seating= and ?crowding= keep spots rated in a range, e.g.
+// ?noise=1-2 for quiet spots; ?type= keeps one type of spot.
+func (h *LocationReviewHandler) GetSpotInsights(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	req := dto.GetSpotInsightsRequest{
+		Noise:    c.Query("noise"),
+		WiFi:     c.Query("wifi"),
+		Outlets:  c.Query("outlets"),
+		Seating:  c.Query("seating"),
+		Crowding: c.Query("crowding"),
+		Type:     c.Query("type"),
+		Limit:    c.QueryInt("limit", 0),
+	}
+
+	insights, err := h.reviewUseCase.GetSpotInsights(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(insights)
+}
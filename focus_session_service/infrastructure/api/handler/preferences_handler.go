@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PreferencesHandler struct {
+	preferencesUseCase usecase.IPreferencesUseCase
+}
+
+func NewPreferencesHandler(preferencesUseCase usecase.IPreferencesUseCase) *PreferencesHandler {
+	return &PreferencesHandler{
+		preferencesUseCase: preferencesUseCase,
+	}
+}
+
+func (h *PreferencesHandler) GetPreferences(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	preferences, err := h.preferencesUseCase.GetPreferences(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(preferences)
+}
+
+func (h *PreferencesHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.UpdatePreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	preferences, err := h.preferencesUseCase.UpdatePreferences(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(preferences)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// exportTimeout bounds how long a single export may stream
+const exportTimeout = 10 * time.Minute
+
+type SessionExportHandler struct {
+	exportUseCase usecase.ISessionExportUseCase
+}
+
+func NewSessionExportHandler(exportUseCase usecase.ISessionExportUseCase) *SessionExportHandler {
+	return &SessionExportHandler{
+		exportUseCase: exportUseCase,
+	}
+}
+
+// ExportSessions streams the user's session history as CSV, JSON or NDJSON
+func (h *SessionExportHandler) ExportSessions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	req := dto.ExportSessionsRequest{
+		Format:    c.Query("format", usecase.ExportCSV),
+		StartDate: c.Query("startDate"),
+		EndDate:   c.Query("endDate"),
+		Timezone:  c.Query("timezone"),
+	}
+
+	export, err := h.exportUseCase.ExportSessions(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+export.FileName+`"`)
+	c.Status(fiber.StatusOK)
+
+	// The body is written after this handler returns, so errors can only be logged
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+
+		if err := export.Write(ctx, w); err != nil {
+			log.Printf("Session export for user %s failed: %v", userID, err)
+		}
+		w.Flush()
+	})
+
+	return nil
+}
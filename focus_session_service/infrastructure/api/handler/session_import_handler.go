@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionImportHandler struct {
+	importUseCase usecase.ISessionImportUseCase
+}
+
+func NewSessionImportHandler(importUseCase usecase.ISessionImportUseCase) *SessionImportHandler {
+	return &SessionImportHandler{
+		importUseCase: importUseCase,
+	}
+}
+
+// ImportICS imports the .ics file uploaded in the "file" form field.
+// With ?dryRun=true nothing is saved.
+func (h *SessionImportHandler) ImportICS(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "An .ics file is required in the \"file\" field",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Could not read the uploaded file",
+		})
+	}
+	defer file.Close()
+
+	report, err := h.importUseCase.ImportICS(c.Context(), userID, file, c.QueryBool("dryRun"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// ImportCSV imports the CSV file uploaded in the "file" form field. The
+// "format" field is toggl, clockify or generic; generic files also need a
+// JSON column mapping in the "mapping" field. With ?dryRun=true nothing is
+// saved and the report previews each session.
+func (h *SessionImportHandler) ImportCSV(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	req := dto.ImportCSVRequest{
+		Format:   c.FormValue("format"),
+		Timezone: c.FormValue("timezone"),
+		DryRun:   c.QueryBool("dryRun"),
+	}
+
+	if mapping := c.FormValue("mapping"); mapping != "" {
+		req.Mapping = &dto.CSVMappingRequest{}
+		if err := json.Unmarshal([]byte(mapping), req.Mapping); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid column mapping",
+			})
+		}
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "A .csv file is required in the \"file\" field",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Could not read the uploaded file",
+		})
+	}
+	defer file.Close()
+
+	report, err := h.importUseCase.ImportCSV(c.Context(), userID, file, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
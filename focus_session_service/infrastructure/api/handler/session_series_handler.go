@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionSeriesHandler struct {
+	seriesUseCase usecase.ISessionSeriesUseCase
+}
+
+func NewSessionSeriesHandler(seriesUseCase usecase.ISessionSeriesUseCase) *SessionSeriesHandler {
+	return &SessionSeriesHandler{
+		seriesUseCase: seriesUseCase,
+	}
+}
+
+func (h *SessionSeriesHandler) CreateSeries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.CreateSeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	series, err := h.seriesUseCase.CreateSeries(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(series)
+}
+
+func (h *SessionSeriesHandler) GetUserSeries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	series, err := h.seriesUseCase.GetUserSeries(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(series)
+}
+
+func (h *SessionSeriesHandler) GetSeriesByID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	seriesID := c.Params("id")
+
+	series, err := h.seriesUseCase.GetSeriesByID(c.Context(), seriesID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(series)
+}
+
+func (h *SessionSeriesHandler) GetOccurrences(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	seriesID := c.Params("id")
+
+	req := dto.GetOccurrencesRequest{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+
+	occurrences, err := h.seriesUseCase.GetOccurrences(c.Context(), seriesID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(occurrences)
+}
+
+func (h *SessionSeriesHandler) UpdateSeries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	seriesID := c.Params("id")
+
+	var req dto.UpdateSeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	series, err := h.seriesUseCase.UpdateSeries(c.Context(), seriesID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(series)
+}
+
+func (h *SessionSeriesHandler) CancelSeries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	seriesID := c.Params("id")
+
+	var req dto.CancelSeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	series, err := h.seriesUseCase.CancelSeries(c.Context(), seriesID, userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(series)
+}
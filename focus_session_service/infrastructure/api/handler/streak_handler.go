@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StreakHandler struct {
+	streakUseCase usecase.IStreakUseCase
+}
+
+func NewStreakHandler(streakUseCase usecase.IStreakUseCase) *StreakHandler {
+	return &StreakHandler{
+		streakUseCase: streakUseCase,
+	}
+}
+
+func (h *StreakHandler) GetStreaks(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	streaks, err := h.streakUseCase.GetStreaks(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(streaks)
+}
+
+func (h *StreakHandler) UpdateStreakSettings(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.UpdateStreakSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	streaks, err := h.streakUseCase.UpdateStreakSettings(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(streaks)
+}
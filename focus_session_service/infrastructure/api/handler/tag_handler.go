@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"focusspot/focussessionservice/application/dto"
+	"focusspot/focussessionservice/application/usecases"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TagHandler struct {
+	tagUseCase usecase.ITagUseCase
+}
+
+func NewTagHandler(tagUseCase usecase.ITagUseCase) *TagHandler {
+	return &TagHandler{
+		tagUseCase: tagUseCase,
+	}
+}
+
+func (h *TagHandler) ListTags(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	tags, err := h.tagUseCase.ListTags(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(tags)
+}
+
+func (h *TagHandler) UpdateTag(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.UpdateTagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	tag, err := h.tagUseCase.UpdateTag(c.Context(), userID, tagParam(c), req)
+	if err != nil {
+		return c.Status(tagErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(tag)
+}
+
+func (h *TagHandler) MergeTags(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req dto.MergeTagsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request payload",
+		})
+	}
+
+	result, err := h.tagUseCase.MergeTags(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (h *TagHandler) DeleteTag(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	result, err := h.tagUseCase.DeleteTag(c.Context(), userID, tagParam(c))
+	if err != nil {
+		return c.Status(tagErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// tagParam returns the tag name from the path, which may be percent-encoded
+func tagParam(c *fiber.Ctx) string {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return c.Params("name")
+	}
+	return name
+}
+
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrTagNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrTagExists):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusBadRequest
+	}
+}
@@ -9,7 +9,22 @@ import (
 )
 
 // SetupRoutes configures all routes for API
-func SetupRoutes(app *fiber.App, sessionHandler *handler.FocusSessionHandler, tokenMaker token.Maker) {
+func SetupRoutes(
+	app *fiber.App,
+	sessionHandler *handler.FocusSessionHandler,
+	seriesHandler *handler.SessionSeriesHandler,
+	calendarHandler *handler.CalendarHandler,
+	importHandler *handler.SessionImportHandler,
+	exportHandler *handler.SessionExportHandler,
+	tagHandler *handler.TagHandler,
+	goalHandler *handler.GoalHandler,
+	streakHandler *handler.StreakHandler,
+	achievementHandler *handler.AchievementHandler,
+	preferencesHandler *handler.PreferencesHandler,
+	locationHandler *handler.LocationHandler,
+	reviewHandler *handler.LocationReviewHandler,
+	tokenMaker token.Maker,
+) {
 	// Middleware
 	app.Use(middleware.LoggerMiddleware())
 
@@ -17,26 +32,92 @@ func SetupRoutes(app *fiber.App, sessionHandler *handler.FocusSessionHandler, to
 	api := app.Group("/api")
 	v1 := api.Group("/v1")
 
+	// Public calendar feed, authenticated by the token in its URL
+	v1.Get("/calendar/feeds/:token", calendarHandler.GetCalendarFeed)
+
 	// Protected routes - all routes need authentication
 	sessions := v1.Group("/focus-sessions")
 	sessions.Use(middleware.AuthMiddleware(tokenMaker))
 
+	// Calendar export and subscription
+	sessions.Get("/calendar.ics", calendarHandler.ExportCalendar)
+	sessions.Get("/calendar/subscription", calendarHandler.GetSubscription)
+	sessions.Post("/calendar/subscription", calendarHandler.CreateSubscription)
+	sessions.Delete("/calendar/subscription", calendarHandler.DeleteSubscription)
+
+	// Session import and export
+	sessions.Post("/import/ics", importHandler.ImportICS)
+	sessions.Post("/import/csv", importHandler.ImportCSV)
+	sessions.Get("/export", exportHandler.ExportSessions)
+
+	// Tag management
+	sessions.Get("/tags", tagHandler.ListTags)
+	sessions.Post("/tags/merge", tagHandler.MergeTags)
+	sessions.Put("/tags/:name", tagHandler.UpdateTag)
+	sessions.Delete("/tags/:name", tagHandler.DeleteTag)
+
+	// Focus goals
+	sessions.Post("/goals", goalHandler.CreateGoal)
+	sessions.Get("/goals", goalHandler.GetUserGoals)
+	sessions.Get("/goals/progress", goalHandler.GetAllGoalProgress)
+	sessions.Get("/goals/:id", goalHandler.GetGoalByID)
+	sessions.Put("/goals/:id", goalHandler.UpdateGoal)
+	sessions.Delete("/goals/:id", goalHandler.DeleteGoal)
+	sessions.Get("/goals/:id/progress", goalHandler.GetGoalProgress)
+
+	// User preferences
+	sessions.Get("/preferences", preferencesHandler.GetPreferences)
+	sessions.Put("/preferences", preferencesHandler.UpdatePreferences)
+
+	// Achievements
+	sessions.Get("/achievements", achievementHandler.GetAchievements)
+
+	// Recurring session series, registered before /:id so "series" is not read as a session ID
+	sessions.Post("/series", seriesHandler.CreateSeries)
+	sessions.Get("/series", seriesHandler.GetUserSeries)
+	sessions.Get("/series/:id", seriesHandler.GetSeriesByID)
+	sessions.Put("/series/:id", seriesHandler.UpdateSeries)
+	sessions.Post("/series/:id/cancel", seriesHandler.CancelSeries)
+	sessions.Get("/series/:id/occurrences", seriesHandler.GetOccurrences)
+
 	// Session management
 	sessions.Post("/", sessionHandler.CreateSession)
 	sessions.Get("/", sessionHandler.GetUserSessions)
 	sessions.Get("/active", sessionHandler.GetActiveSession)
+	sessions.Get("/search", sessionHandler.SearchSessions)
 	sessions.Get("/:id", sessionHandler.GetSessionByID)
 	sessions.Put("/:id", sessionHandler.UpdateSession)
 	sessions.Delete("/:id", sessionHandler.DeleteSession)
 	
 	// Session status management
 	sessions.Post("/:id/start", sessionHandler.StartSession)
+	sessions.Post("/:id/pause", sessionHandler.PauseSession)
+	sessions.Post("/:id/resume", sessionHandler.ResumeSession)
 	sessions.Post("/:id/end", sessionHandler.EndSession)
 	sessions.Post("/:id/cancel", sessionHandler.CancelSession)
 	
 	// Productivity analytics
 	sessions.Get("/analytics/stats", sessionHandler.GetProductivityStats)
 	sessions.Get("/analytics/trends", sessionHandler.GetProductivityTrends)
+	sessions.Get("/analytics/heatmap", sessionHandler.GetProductivityHeatmap)
+	sessions.Get("/analytics/streaks", streakHandler.GetStreaks)
+	sessions.Put("/analytics/streaks/settings", streakHandler.UpdateStreakSettings)
+
+	// Focus spots, shared by all users
+	locations := v1.Group("/locations")
+	locations.Use(middleware.AuthMiddleware(tokenMaker))
+
+	locations.Post("/", locationHandler.CreateLocation)
+	locations.Get("/", locationHandler.GetLocations)
+	locations.Get("/nearby", locationHandler.GetNearbyLocations)
+	locations.Get("/recommendations", locationHandler.GetRecommendations)
+	locations.Get("/insights", reviewHandler.GetSpotInsights)
+	locations.Get("/:id", locationHandler.GetLocationByID)
+	locations.Put("/:id", locationHandler.UpdateLocation)
+	locations.Delete("/:id", locationHandler.DeleteLocation)
+	locations.Get("/:id/reviews", reviewHandler.GetLocationReviews)
+	locations.Put("/:id/review", reviewHandler.ReviewLocation)
+	locations.Delete("/:id/review", reviewHandler.DeleteReview)
 
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
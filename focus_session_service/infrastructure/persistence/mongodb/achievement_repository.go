@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"context"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoAchievementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAchievementRepository(db *mongo.Database) interfaces.IAchievementRepository {
+	collection := db.Collection("achievements")
+
+	// Each achievement unlocks once per user
+	_, err := collection.Indexes().CreateOne(
+		context.Background(),
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "key", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoAchievementRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoAchievementRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.Achievement, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "unlockedAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var achievements []*entity.Achievement
+	if err := cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func (r *mongoAchievementRepository) Unlock(ctx context.Context, achievement *entity.Achievement) error {
+	if achievement.ID.IsZero() {
+		achievement.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, achievement)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
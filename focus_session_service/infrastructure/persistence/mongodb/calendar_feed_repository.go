@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoCalendarFeedRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCalendarFeedRepository(db *mongo.Database) interfaces.ICalendarFeedRepository {
+	collection := db.Collection("calendar_feeds")
+
+	// Create indexes
+	_, err := collection.Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "userId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "token", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoCalendarFeedRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoCalendarFeedRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.CalendarFeed, error) {
+	var feed entity.CalendarFeed
+
+	err := r.collection.FindOne(ctx, bson.M{"userId": userID}).Decode(&feed)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // No subscription yet, not an error
+		}
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (r *mongoCalendarFeedRepository) GetByToken(ctx context.Context, token string) (*entity.CalendarFeed, error) {
+	var feed entity.CalendarFeed
+
+	err := r.collection.FindOne(ctx, bson.M{"token": token}).Decode(&feed)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("calendar feed not found")
+		}
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+// Upsert stores the user's feed, replacing any previous token
+func (r *mongoCalendarFeedRepository) Upsert(ctx context.Context, feed *entity.CalendarFeed) error {
+	if feed.ID.IsZero() {
+		feed.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"userId": feed.UserID},
+		bson.M{
+			"$set": bson.M{
+				"token":     feed.Token,
+				"createdAt": feed.CreatedAt,
+			},
+			"$setOnInsert": bson.M{
+				"_id": feed.ID,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
+
+func (r *mongoCalendarFeedRepository) Delete(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"userId": userID})
+	return err
+}
@@ -0,0 +1,219 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoDailyRollupRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDailyRollupRepository(db *mongo.Database) interfaces.IDailyRollupRepository {
+	collection := db.Collection("focus_daily_rollups")
+
+	// One rollup per user and day
+	_, err := collection.Indexes().CreateOne(
+		context.Background(),
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1}, {Key: "day", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoDailyRollupRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoDailyRollupRepository) GetTimezone(ctx context.Context, userID primitive.ObjectID) (string, error) {
+	var rollup struct {
+		Timezone string `bson:"timezone"`
+	}
+
+	err := r.collection.FindOne(
+		ctx,
+		bson.M{"userId": userID},
+		options.FindOne().SetProjection(bson.M{"timezone": 1}),
+	).Decode(&rollup)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return rollup.Timezone, nil
+}
+
+func (r *mongoDailyRollupRepository) GetRange(ctx context.Context, userID primitive.ObjectID, fromDay, toDay string) ([]*entity.DailyRollup, error) {
+	filter := bson.M{
+		"userId": userID,
+		"day": bson.M{
+			"$gte": fromDay,
+			"$lte": toDay,
+		},
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "day", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rollups []*entity.DailyRollup
+	if err := cursor.All(ctx, &rollups); err != nil {
+		return nil, err
+	}
+
+	for _, rollup := range rollups {
+		mapRollupKeys(rollup, unescapeKey)
+	}
+
+	return rollups, nil
+}
+
+// Add increments every sum of the stored rollup, so that concurrent changes
+// to the same day do not overwrite each other
+func (r *mongoDailyRollupRepository) Add(ctx context.Context, rollup *entity.DailyRollup) error {
+	inc := bson.M{}
+	incMetrics(inc, "totals", rollup.Totals)
+	groups := map[string]map[string]entity.RollupMetrics{
+		"byHour":         rollup.ByHour,
+		"byLocation":     rollup.ByLocation,
+		"byLocationType": rollup.ByLocationType,
+		"byTag":          rollup.ByTag,
+	}
+	for field, group := range groups {
+		for key, metrics := range group {
+			incMetrics(inc, field+"."+escapeKey(key), metrics)
+		}
+	}
+
+	filter := bson.M{
+		"userId": rollup.UserID,
+		"day":    rollup.Day,
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		filter,
+		bson.M{
+			"$inc": inc,
+			"$set": bson.M{
+				"timezone":  rollup.Timezone,
+				"updatedAt": time.Now(),
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	if rollup.Totals.Sessions < 0 {
+		filter["totals.sessions"] = bson.M{"$lte": 0}
+		_, err = r.collection.DeleteOne(ctx, filter)
+	}
+
+	return err
+}
+
+func (r *mongoDailyRollupRepository) ReplaceUser(ctx context.Context, userID primitive.ObjectID, rollups []*entity.DailyRollup) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return err
+	}
+
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]interface{}, 0, len(rollups))
+	for _, rollup := range rollups {
+		stored := *rollup
+		stored.ID = primitive.NewObjectID()
+		stored.UpdatedAt = now
+		mapRollupKeys(&stored, escapeKey)
+		documents = append(documents, &stored)
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents)
+	return err
+}
+
+func incMetrics(inc bson.M, prefix string, metrics entity.RollupMetrics) {
+	inc[prefix+".sessions"] = metrics.Sessions
+	inc[prefix+".minutes"] = metrics.Minutes
+	inc[prefix+".pomodoros"] = metrics.Pomodoros
+	inc[prefix+".ratingSum"] = metrics.RatingSum
+	inc[prefix+".ratingCount"] = metrics.RatingCount
+	inc[prefix+".focusSum"] = metrics.FocusSum
+	inc[prefix+".focusCount"] = metrics.FocusCount
+	inc[prefix+".energySum"] = metrics.EnergySum
+	inc[prefix+".energyCount"] = metrics.EnergyCount
+	inc[prefix+".moodSum"] = metrics.MoodSum
+	inc[prefix+".moodCount"] = metrics.MoodCount
+	inc[prefix+".distractionsSum"] = metrics.DistractionsSum
+	inc[prefix+".distractionsCount"] = metrics.DistractionsCount
+
+	for version, score := range metrics.Scores {
+		inc[prefix+".scores."+version+".sum"] = score.Sum
+		inc[prefix+".scores."+version+".positive"] = score.Positive
+	}
+}
+
+// Location names and tags become field names, where dots and dollars are
+// not usable in update paths. They are escaped like URLs, and the empty name
+// becomes a lone percent sign, which no escaped name can be.
+var (
+	keyEscaper   = strings.NewReplacer("%", "%25", ".", "%2E", "$", "%24")
+	keyUnescaper = strings.NewReplacer("%2E", ".", "%24", "$", "%25", "%")
+)
+
+func escapeKey(key string) string {
+	if key == "" {
+		return "%"
+	}
+	return keyEscaper.Replace(key)
+}
+
+func unescapeKey(key string) string {
+	if key == "%" {
+		return ""
+	}
+	return keyUnescaper.Replace(key)
+}
+
+// mapRollupKeys rewrites the group keys of a rollup with mapKey
+func mapRollupKeys(rollup *entity.DailyRollup, mapKey func(string) string) {
+	remap := func(groups map[string]entity.RollupMetrics) map[string]entity.RollupMetrics {
+		mapped := make(map[string]entity.RollupMetrics, len(groups))
+		for key, metrics := range groups {
+			mapped[mapKey(key)] = metrics
+		}
+		return mapped
+	}
+
+	rollup.ByLocation = remap(rollup.ByLocation)
+	rollup.ByLocationType = remap(rollup.ByLocationType)
+	rollup.ByTag = remap(rollup.ByTag)
+}
@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"fmt"
+	"focusspot/focussessionservice/domain/entity"
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var fixtureTags = []string{"deep-work", "study", "writing", "email", "planning", "reading"}
+
+var fixtureLocations = []*entity.LocationDetails{
+	{Name: "Home", Type: "home"},
+	{Name: "Central Library", Type: "library"},
+	{Name: "The Workshop Cafe", Type: "cafe"},
+	{Name: "Office", Type: "office"},
+	{Name: "Park bench"}, // no type
+	{},                   // no name either
+}
+
+// generateSessions makes sessions spread over the two years before now. Each
+// metric is left out now and then, and some sessions are cancelled, still
+// planned or deleted, so that every branch of the aggregation is exercised.
+func generateSessions(r *rand.Rand, userID primitive.ObjectID, now time.Time, count int) []*entity.FocusSession {
+	sessions := make([]*entity.FocusSession, 0, count+len(edgeTimes(now)))
+
+	maybe := func(min, max int) *int {
+		if r.Intn(5) == 0 {
+			return nil
+		}
+		value := min + r.Intn(max-min+1)
+		return &value
+	}
+
+	add := func(start time.Time) {
+		session := &entity.FocusSession{
+			UserID:    userID,
+			Title:     fmt.Sprintf("Fixture %d", len(sessions)+1),
+			StartTime: start,
+			Duration:  []int{0, 15, 25, 45, 60, 90}[r.Intn(6)],
+			Status:    entity.StatusCompleted,
+			Tags:      []string{},
+			CreatedAt: start,
+			UpdatedAt: start,
+			Active:    r.Intn(20) != 0,
+		}
+
+		switch r.Intn(10) {
+		case 0:
+			session.Status = entity.StatusCancelled
+		case 1:
+			session.Status = entity.StatusPlanned
+		}
+
+		// A few completed sessions have no actual duration
+		if session.Status == entity.StatusCompleted && r.Intn(25) != 0 {
+			actual := r.Intn(150)
+			session.ActualDuration = &actual
+			end := start.Add(time.Duration(actual) * time.Minute)
+			session.EndTime = &end
+
+			session.Rating = maybe(1, 5)
+			session.Focus = maybe(1, 10)
+			session.Energy = maybe(1, 10)
+			session.Mood = maybe(1, 10)
+			session.Distractions = maybe(0, 15)
+		}
+
+		for _, tag := range fixtureTags {
+			if r.Intn(4) == 0 {
+				session.Tags = append(session.Tags, tag)
+			}
+		}
+
+		if i := r.Intn(len(fixtureLocations) + 2); i < len(fixtureLocations) {
+			location := *fixtureLocations[i]
+			session.LocationDetails = &location
+		}
+
+		if r.Intn(4) == 0 {
+			session.Mode = entity.ModePomodoro
+			session.Pomodoro = &entity.PomodoroState{CompletedPomodoros: r.Intn(6)}
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	for i := 0; i < count; i++ {
+		add(now.Add(-time.Duration(r.Int63n(int64(2 * 365 * 24 * time.Hour)))))
+	}
+
+	// Sessions on the edges of days, weeks and daylight saving changes
+	for _, start := range edgeTimes(now) {
+		add(start)
+	}
+
+	return sessions
+}
+
+// edgeTimes are instants on or next to period boundaries in the checked
+// timezones
+func edgeTimes(now time.Time) []time.Time {
+	var times []time.Time
+	year := now.Year() - 1
+
+	for _, name := range timezones {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			continue
+		}
+
+		for _, day := range []time.Time{
+			time.Date(year, time.January, 1, 0, 0, 0, 0, loc),
+			time.Date(year, time.March, 9, 2, 30, 0, 0, loc),
+			time.Date(year, time.April, 6, 1, 45, 0, 0, loc),
+			time.Date(year, time.July, 1, 0, 0, 0, 0, loc),
+			time.Date(year, time.October, 5, 2, 0, 0, 0, loc),
+			time.Date(year, time.November, 2, 1, 30, 0, 0, loc),
+			time.Date(year, time.December, 31, 23, 59, 59, 0, loc),
+		} {
+			times = append(times, day, day.Add(-time.Second), day.Add(time.Second))
+		}
+	}
+
+	return times
+}
@@ -3,10 +3,8 @@ package mongodb
 import (
 	"context"
 	"errors"
-	"fmt"
 	"focusspot/focussessionservice/domain/entity"
 	"focusspot/focussessionservice/domain/interfaces"
-	"sort"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -27,6 +25,8 @@ func NewMongoFocusSessionRepository(db *mongo.Database) interfaces.IFocusSession
 		context.Background(),
 		[]mongo.IndexModel{
 			{
+				// Also serves cursor pagination; the _id tie-break only sorts
+				// sessions that start at the same time
 				Keys: bson.D{
 					{Key: "userId", Value: 1},
 					{Key: "startTime", Value: -1},
@@ -37,6 +37,48 @@ func NewMongoFocusSessionRepository(db *mongo.Database) interfaces.IFocusSession
 					{Key: "userId", Value: 1}, {Key: "status", Value: 1},
 				},
 			},
+			{
+				// One materialized session per series occurrence
+				Keys: bson.D{
+					{Key: "seriesId", Value: 1}, {Key: "occurrenceTime", Value: 1},
+				},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"seriesId": bson.M{"$exists": true}}),
+			},
+			{
+				// Full-text search, scoped to one user. Title matches rank highest.
+				Keys: bson.D{
+					{Key: "userId", Value: 1},
+					{Key: "title", Value: "text"},
+					{Key: "description", Value: "text"},
+					{Key: "notes", Value: "text"},
+				},
+				Options: options.Index().
+					SetName("session_text").
+					SetWeights(bson.D{
+						{Key: "title", Value: 5},
+						{Key: "description", Value: 2},
+						{Key: "notes", Value: 1},
+					}),
+			},
+			{
+				// Sessions at a saved spot
+				Keys: bson.D{
+					{Key: "locationId", Value: 1}, {Key: "userId", Value: 1},
+				},
+				Options: options.Index().
+					SetPartialFilterExpression(bson.M{"locationId": bson.M{"$exists": true}}),
+			},
+			{
+				// Imported events are deduplicated by their UID
+				Keys: bson.D{
+					{Key: "userId", Value: 1}, {Key: "externalUid", Value: 1},
+				},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"externalUid": bson.M{"$exists": true}}),
+			},
 		})
 
 	if err != nil {
@@ -73,19 +115,27 @@ func (r *mongoFocusSessionRepository) GetByID(ctx context.Context, id primitive.
 	return &session, nil
 }
 
-func (r *mongoFocusSessionRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID, limit, offset int) ([]*entity.FocusSession, error) {
+func (r *mongoFocusSessionRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter, limit, offset int) ([]*entity.FocusSession, error) {
+	sortField := filter.SortBy
+	if sortField == "" {
+		sortField = entity.SortByStartTime
+	}
+
+	direction := 1
+	if filter.SortDesc {
+		direction = -1
+	}
+
 	findOptions := options.Find()
 	findOptions.SetLimit(int64(limit))
 	findOptions.SetSkip(int64(offset))
-	findOptions.SetSort(bson.D{{Key: "startTime", Value: -1}})
-
-	// Add filter for active sessions only
-	filter := bson.M{
-		"userId": userID,
-		"active": true,
-	}
+	// _id breaks ties so that pages do not overlap
+	findOptions.SetSort(bson.D{
+		{Key: string(sortField), Value: direction},
+		{Key: "_id", Value: direction},
+	})
 
-	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	cursor, err := r.collection.Find(ctx, sessionFilterQuery(userID, filter), findOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -99,12 +149,244 @@ func (r *mongoFocusSessionRepository) GetByUserID(ctx context.Context, userID pr
 	return sessions, nil
 }
 
+// GetPageByUserID returns up to limit sessions following the cursor, or
+// preceding it when cursor.Before is set, in the filter's start time order.
+// Without a cursor it returns the first page. Results are always in list
+// order.
+func (r *mongoFocusSessionRepository) GetPageByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter, cursor *entity.SessionCursor, limit int) ([]*entity.FocusSession, error) {
+	query := sessionFilterQuery(userID, filter)
+
+	descending := filter.SortDesc
+	if cursor != nil && cursor.Before {
+		// Walk backwards from the cursor, then restore list order below
+		descending = !descending
+	}
+
+	direction, comparison := 1, "$gt"
+	if descending {
+		direction, comparison = -1, "$lt"
+	}
+
+	if cursor != nil {
+		query["$or"] = bson.A{
+			bson.M{"startTime": bson.M{comparison: cursor.StartTime}},
+			bson.M{"startTime": cursor.StartTime, "_id": bson.M{comparison: cursor.ID}},
+		}
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{
+		{Key: "startTime", Value: direction},
+		{Key: "_id", Value: direction},
+	})
+
+	results, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close(ctx)
+
+	var sessions []*entity.FocusSession
+	if err := results.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Before {
+		for i, j := 0, len(sessions)-1; i < j; i, j = i+1, j-1 {
+			sessions[i], sessions[j] = sessions[j], sessions[i]
+		}
+	}
+
+	return sessions, nil
+}
+
+// SearchByUserID runs the filter's text search and returns matches by
+// relevance, most recent first among equals
+func (r *mongoFocusSessionRepository) SearchByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter, limit, offset int) ([]*entity.SessionSearchResult, error) {
+	if filter.Text == "" {
+		return nil, errors.New("search text is required")
+	}
+
+	score := bson.M{"$meta": "textScore"}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(offset))
+	findOptions.SetProjection(bson.M{"score": score})
+	findOptions.SetSort(bson.D{
+		{Key: "score", Value: score},
+		{Key: "startTime", Value: -1},
+		{Key: "_id", Value: -1},
+	})
+
+	cursor, err := r.collection.Find(ctx, sessionFilterQuery(userID, filter), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []*entity.SessionSearchResult
+	for cursor.Next(ctx) {
+		var hit struct {
+			entity.FocusSession `bson:",inline"`
+			Score               float64 `bson:"score"`
+		}
+		if err := cursor.Decode(&hit); err != nil {
+			return nil, err
+		}
+
+		session := hit.FocusSession
+		results = append(results, &entity.SessionSearchResult{
+			Session: &session,
+			Score:   hit.Score,
+		})
+	}
+
+	return results, cursor.Err()
+}
+
+// CountByUserID counts the sessions matching the filter, ignoring its sort
+func (r *mongoFocusSessionRepository) CountByUserID(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter) (int64, error) {
+	return r.collection.CountDocuments(ctx, sessionFilterQuery(userID, filter))
+}
+
+// GetTotals counts the sessions matching the filter and adds up their actual
+// durations
+func (r *mongoFocusSessionRepository) GetTotals(ctx context.Context, userID primitive.ObjectID, filter entity.SessionFilter) (*entity.SessionTotals, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: sessionFilterQuery(userID, filter)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      nil,
+			"sessions": bson.M{"$sum": 1},
+			"minutes":  bson.M{"$sum": bson.M{"$ifNull": bson.A{"$actualDuration", 0}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	totals := &entity.SessionTotals{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(totals); err != nil {
+			return nil, err
+		}
+	}
+
+	return totals, cursor.Err()
+}
+
+// sessionFilterQuery builds the query for a user's active sessions matching
+// the filter
+func sessionFilterQuery(userID primitive.ObjectID, filter entity.SessionFilter) bson.M {
+	query := bson.M{
+		"userId": userID,
+		"active": true,
+	}
+
+	if filter.Text != "" {
+		query["$text"] = bson.M{"$search": filter.Text}
+	}
+
+	if !filter.StartDate.IsZero() || !filter.EndDate.IsZero() {
+		startTime := bson.M{}
+		if !filter.StartDate.IsZero() {
+			startTime["$gte"] = filter.StartDate
+		}
+		if !filter.EndDate.IsZero() {
+			startTime["$lte"] = filter.EndDate
+		}
+		query["startTime"] = startTime
+	}
+
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": filter.Statuses}
+	}
+
+	if len(filter.Tags) > 0 {
+		operator := "$in"
+		if filter.MatchAllTags {
+			operator = "$all"
+		}
+		query["tags"] = bson.M{operator: filter.Tags}
+	}
+
+	if filter.LocationID != nil {
+		query["locationId"] = *filter.LocationID
+	}
+
+	if filter.LocationType != "" {
+		query["locationDetails.type"] = filter.LocationType
+	}
+
+	if rating := rangeQuery(filter.MinRating, filter.MaxRating); rating != nil {
+		query["rating"] = rating
+	}
+
+	if focus := rangeQuery(filter.MinFocus, filter.MaxFocus); focus != nil {
+		query["focus"] = focus
+	}
+
+	if filter.MinDuration != nil || filter.MaxDuration != nil {
+		// Finished sessions are measured by how long they actually ran
+		duration := bson.M{"$ifNull": bson.A{"$actualDuration", "$duration"}}
+		var conditions bson.A
+		if filter.MinDuration != nil {
+			conditions = append(conditions, bson.M{"$gte": bson.A{duration, *filter.MinDuration}})
+		}
+		if filter.MaxDuration != nil {
+			conditions = append(conditions, bson.M{"$lte": bson.A{duration, *filter.MaxDuration}})
+		}
+		query["$expr"] = bson.M{"$and": conditions}
+	}
+
+	return query
+}
+
+// rangeQuery returns an inclusive range condition, or nil when unbounded
+func rangeQuery(low, high *int) bson.M {
+	if low == nil && high == nil {
+		return nil
+	}
+
+	condition := bson.M{}
+	if low != nil {
+		condition["$gte"] = *low
+	}
+	if high != nil {
+		condition["$lte"] = *high
+	}
+	return condition
+}
+
+func (r *mongoFocusSessionRepository) GetByExternalUID(ctx context.Context, userID primitive.ObjectID, externalUID string) (*entity.FocusSession, error) {
+	var session entity.FocusSession
+
+	err := r.collection.FindOne(ctx, bson.M{
+		"userId":      userID,
+		"externalUid": externalUID,
+	}).Decode(&session)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // Not imported yet, not an error
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
 func (r *mongoFocusSessionRepository) GetActiveByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.FocusSession, error) {
 	var session entity.FocusSession
 
+	// A paused session is still the user's current session
 	err := r.collection.FindOne(ctx, bson.M{
 		"userId": userID,
-		"status": entity.StatusActive,
+		"status": bson.M{"$in": []entity.SessionStatus{entity.StatusActive, entity.StatusPaused}},
 		"active": true,
 	}).Decode(&session)
 
@@ -145,6 +427,257 @@ func (r *mongoFocusSessionRepository) GetSessionsByDateRange(ctx context.Context
 	return sessions, nil
 }
 
+// UpsertOccurrence stores a series occurrence unless one already exists for
+// the same series and occurrence time. The unique (seriesId, occurrenceTime)
+// index settles concurrent upserts: the one that loses has nothing to store.
+func (r *mongoFocusSessionRepository) UpsertOccurrence(ctx context.Context, session *entity.FocusSession) error {
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"seriesId":       session.SeriesID,
+			"occurrenceTime": session.OccurrenceTime,
+		},
+		bson.M{"$setOnInsert": session},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+
+	return err
+}
+
+// GetBySeriesID returns the materialized occurrences of a series in [from, to),
+// including ones the user deleted so they are not materialized again. A zero
+// `to` leaves the window open.
+func (r *mongoFocusSessionRepository) GetBySeriesID(ctx context.Context, seriesID primitive.ObjectID, from, to time.Time) ([]*entity.FocusSession, error) {
+	occurrenceTime := bson.M{"$gte": from}
+	if !to.IsZero() {
+		occurrenceTime["$lt"] = to
+	}
+
+	filter := bson.M{
+		"seriesId":       seriesID,
+		"occurrenceTime": occurrenceTime,
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "occurrenceTime", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*entity.FocusSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// MoveOccurrences links the occurrences of a series from `from` onward to
+// another series, as when the series is split
+func (r *mongoFocusSessionRepository) MoveOccurrences(ctx context.Context, seriesID, newSeriesID primitive.ObjectID, from time.Time) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"seriesId":       seriesID,
+			"occurrenceTime": bson.M{"$gte": from},
+		},
+		bson.M{
+			"$set": bson.M{
+				"seriesId":  newSeriesID,
+				"updatedAt": time.Now(),
+			},
+		},
+	)
+
+	return err
+}
+
+// CancelPlannedOccurrences cancels the planned occurrences of a series from `from` onward
+func (r *mongoFocusSessionRepository) CancelPlannedOccurrences(ctx context.Context, seriesID primitive.ObjectID, from time.Time) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"seriesId":       seriesID,
+			"occurrenceTime": bson.M{"$gte": from},
+			"status":         entity.StatusPlanned,
+		},
+		bson.M{
+			"$set": bson.M{
+				"status":    entity.StatusCancelled,
+				"updatedAt": time.Now(),
+			},
+		},
+	)
+
+	return err
+}
+
+// ForEachSession calls fn for each of the user's sessions in the date range,
+// oldest first, decoding one document at a time from the cursor. A zero
+// start or end date leaves that side of the range open.
+func (r *mongoFocusSessionRepository) ForEachSession(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	startDate, endDate time.Time,
+	fn func(*entity.FocusSession) error,
+) error {
+	filter := bson.M{
+		"userId": userID,
+		"active": true,
+	}
+
+	startTime := bson.M{}
+	if !startDate.IsZero() {
+		startTime["$gte"] = startDate
+	}
+	if !endDate.IsZero() {
+		startTime["$lte"] = endDate
+	}
+	if len(startTime) > 0 {
+		filter["startTime"] = startTime
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "startTime", Value: 1}, {Key: "_id", Value: 1}})
+	findOptions.SetBatchSize(500)
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var session entity.FocusSession
+		if err := cursor.Decode(&session); err != nil {
+			return err
+		}
+
+		if err := fn(&session); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// GetUserIDs returns every user who has sessions
+func (r *mongoFocusSessionRepository) GetUserIDs(ctx context.Context) ([]primitive.ObjectID, error) {
+	values, err := r.collection.Distinct(ctx, "userId", bson.M{"active": true})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		if userID, ok := value.(primitive.ObjectID); ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// GetTagUsage counts the user's sessions and completed minutes per tag, most
+// used first
+func (r *mongoFocusSessionRepository) GetTagUsage(ctx context.Context, userID primitive.ObjectID) ([]*entity.TagUsage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"userId": userID,
+			"active": true,
+			"tags":   bson.M{"$exists": true, "$ne": bson.A{}},
+		}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$tags",
+			"sessions": bson.M{"$sum": 1},
+			"minutes": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$status", entity.StatusCompleted}},
+				bson.M{"$ifNull": bson.A{"$actualDuration", 0}},
+				0,
+			}}},
+			"lastUsed": bson.M{"$max": "$startTime"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "sessions", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var usage []*entity.TagUsage
+	if err := cursor.All(ctx, &usage); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// ReplaceTag renames a tag on all of the user's sessions, deleted ones
+// included. Sessions that already have the new tag just lose the old one.
+// It returns how many sessions changed.
+func (r *mongoFocusSessionRepository) ReplaceTag(ctx context.Context, userID primitive.ObjectID, from, to string) (int64, error) {
+	now := time.Now()
+
+	// Drop the old tag where the new one is already present, so that the
+	// rename below cannot create a duplicate
+	merged, err := r.collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "tags": bson.M{"$all": bson.A{from, to}}},
+		bson.M{
+			"$pull": bson.M{"tags": from},
+			"$set":  bson.M{"updatedAt": now},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	renamed, err := r.collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "tags": from},
+		bson.M{"$set": bson.M{
+			"tags.$[tag]": to,
+			"updatedAt":   now,
+		}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"tag": from}},
+		}),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return merged.ModifiedCount + renamed.ModifiedCount, nil
+}
+
+// RemoveTag removes a tag from all of the user's sessions and returns how
+// many sessions changed
+func (r *mongoFocusSessionRepository) RemoveTag(ctx context.Context, userID primitive.ObjectID, name string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "tags": name},
+		bson.M{
+			"$pull": bson.M{"tags": name},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
 func (r *mongoFocusSessionRepository) Update(ctx context.Context, session *entity.FocusSession) error {
 	session.UpdatedAt = time.Now()
 
@@ -184,7 +717,13 @@ func (r *mongoFocusSessionRepository) EndSession(ctx context.Context, id primiti
 		return err
 	}
 
-	actualDuration := int(endTime.Sub(session.StartTime).Minutes())
+	// Close a pause that is still open so it ends with the session
+	if session.IsPaused() {
+		session.Pauses[len(session.Pauses)-1].ResumedAt = &endTime
+	}
+
+	// Paused time does not count as focus time
+	actualDuration := session.FocusedMinutes(endTime)
 
 	update := bson.M{
 		"$set": bson.M{
@@ -195,6 +734,15 @@ func (r *mongoFocusSessionRepository) EndSession(ctx context.Context, id primiti
 		},
 	}
 
+	if len(session.Pauses) > 0 {
+		update["$set"].(bson.M)["pauses"] = session.Pauses
+	}
+
+	// Record how many pomodoros were finished before the session ended
+	if session.SyncPomodoro(endTime) != nil {
+		update["$set"].(bson.M)["pomodoro"] = session.Pomodoro
+	}
+
 	if notes != "" {
 		update["$set"].(bson.M)["notes"] = notes
 	}
@@ -228,554 +776,262 @@ func (r *mongoFocusSessionRepository) EndSession(ctx context.Context, id primiti
 	return err
 }
 
-func (r *mongoFocusSessionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+func (r *mongoFocusSessionRepository) StartSession(ctx context.Context, id primitive.ObjectID, startTime time.Time) error {
 	_, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": id},
 		bson.M{
 			"$set": bson.M{
-				"active": false,
+				"status":    entity.StatusActive,
+				"startTime": startTime,
+				"updatedAt": time.Now(),
 			},
-		})
+		},
+	)
+
 	return err
 }
 
-func (r *mongoFocusSessionRepository) GetProductivityStats(
-	ctx context.Context,
-	userID primitive.ObjectID,
-	startDate, endDate time.Time,
-) (*entity.ProductivityStats, error) {
-	// Get completed sessions in date range
-	filter := bson.M{
-		"userId": userID,
-		"startTime": bson.M{
-			"$gte": startDate,
-			"$lte": endDate,
+func (r *mongoFocusSessionRepository) PauseSession(ctx context.Context, id primitive.ObjectID, pausedAt time.Time) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": entity.StatusActive},
+		bson.M{
+			"$set": bson.M{
+				"status":    entity.StatusPaused,
+				"updatedAt": time.Now(),
+			},
+			"$push": bson.M{
+				"pauses": entity.PauseInterval{PausedAt: pausedAt},
+			},
 		},
-		"status": entity.StatusCompleted,
-		"active": true,
-	}
-
-	cursor, err := r.collection.Find(ctx, filter)
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var sessions []*entity.FocusSession
-	if err := cursor.All(ctx, &sessions); err != nil {
-		return nil, err
+		return err
 	}
 
-	stats := &entity.ProductivityStats{
-		TotalSessions:              len(sessions),
-		ProductivityByDay:          make(map[time.Weekday]float64),
-		ProductivityByTime:         make(map[entity.TimeOfDay]float64),
-		ProductivityByLocation:     make(map[string]float64),
-		ProductivityByLocationType: make(map[string]float64),
-	}
-
-	if len(sessions) == 0 {
-		return stats, nil
-	}
-
-	// Counter structures for analysis
-	type MetricCounter struct {
-		Count         int
-		TotalScore    float64
-		TotalRating   int
-		TotalFocus    int
-		TotalEnergy   int
-		TotalMood     int
-		TotalDistract int
-		TotalDuration int
-	}
-
-	dayCounter := make(map[time.Weekday]*MetricCounter)
-	timeCounter := make(map[entity.TimeOfDay]*MetricCounter)
-	locationCounter := make(map[string]*MetricCounter)
-	locationTypeCounter := make(map[string]*MetricCounter)
-
-	// Initialize counters for all possible values
-	for day := time.Sunday; day <= time.Saturday; day++ {
-		dayCounter[day] = &MetricCounter{}
-	}
-
-	for _, tod := range []entity.TimeOfDay{entity.EarlyMorning, entity.LateMorning, entity.Afternoon, entity.Evening, entity.Night} {
-		timeCounter[tod] = &MetricCounter{}
-	}
-
-	var completedCount, cancelledCount int
-	var totalRating, ratingCount int
-	var totalFocus, focusCount int
-	var totalEnergy, energyCount int
-	var totalMood, moodCount int
-	var totalDistract, distractCount int
-	var totalDuration int
-
-	// Process each session
-	for _, session := range sessions {
-		// Skip sessions with no actual duration
-		if session.ActualDuration != nil {
-			completedCount++
-			totalDuration += *session.ActualDuration
-
-			// Calculate productivity score
-			prodScore := session.CalculateProductivityScore()
-
-			// Process by day of week
-			day := session.StartTime.Weekday()
-			counter := dayCounter[day]
-			counter.Count++
-			counter.TotalScore += prodScore
-			counter.TotalDuration += *session.ActualDuration
-
-			// Process by time of day
-			tod := entity.GetTimeOfDay(session.StartTime)
-			timeCounter[tod].Count++
-			timeCounter[tod].TotalScore += prodScore
-			timeCounter[tod].TotalDuration += *session.ActualDuration
-
-			// Process by location
-			if session.LocationDetails != nil {
-				// By location name
-				locName := session.LocationDetails.Name
-				if _, exists := locationCounter[locName]; !exists {
-					locationCounter[locName] = &MetricCounter{}
-				}
-				locationCounter[locName].Count++
-				locationCounter[locName].TotalScore += prodScore
-				locationCounter[locName].TotalDuration += *session.ActualDuration
-
-				// By location type
-				if session.LocationDetails.Type != "" {
-					locType := session.LocationDetails.Type
-					if _, exists := locationTypeCounter[locType]; !exists {
-						locationTypeCounter[locType] = &MetricCounter{}
-					}
-					locationTypeCounter[locType].Count++
-					locationTypeCounter[locType].TotalScore += prodScore
-					locationTypeCounter[locType].TotalDuration += *session.ActualDuration
-				}
-			}
-
-			// Collect metrics
-			if session.Rating != nil {
-				totalRating += *session.Rating
-				ratingCount++
-
-				// Add to counters
-				dayCounter[day].TotalRating += *session.Rating
-				timeCounter[tod].TotalRating += *session.Rating
-
-				if session.LocationDetails != nil {
-					locationCounter[session.LocationDetails.Name].TotalRating += *session.Rating
-					if session.LocationDetails.Type != "" {
-						locationTypeCounter[session.LocationDetails.Type].TotalRating += *session.Rating
-					}
-				}
-			}
-
-			if session.Focus != nil {
-				totalFocus += *session.Focus
-				focusCount++
-
-				// Add to counters
-				dayCounter[day].TotalFocus += *session.Focus
-				timeCounter[tod].TotalFocus += *session.Focus
-
-				if session.LocationDetails != nil {
-					locationCounter[session.LocationDetails.Name].TotalFocus += *session.Focus
-					if session.LocationDetails.Type != "" {
-						locationTypeCounter[session.LocationDetails.Type].TotalFocus += *session.Focus
-					}
-				}
-			}
-
-			if session.Energy != nil {
-				totalEnergy += *session.Energy
-				energyCount++
-
-				// Add to counters
-				dayCounter[day].TotalEnergy += *session.Energy
-				timeCounter[tod].TotalEnergy += *session.Energy
-
-				if session.LocationDetails != nil {
-					locationCounter[session.LocationDetails.Name].TotalEnergy += *session.Energy
-					if session.LocationDetails.Type != "" {
-						locationTypeCounter[session.LocationDetails.Type].TotalEnergy += *session.Energy
-					}
-				}
-			}
-
-			if session.Mood != nil {
-				totalMood += *session.Mood
-				moodCount++
-
-				// Add to counters
-				dayCounter[day].TotalMood += *session.Mood
-				timeCounter[tod].TotalMood += *session.Mood
-
-				if session.LocationDetails != nil {
-					locationCounter[session.LocationDetails.Name].TotalMood += *session.Mood
-					if session.LocationDetails.Type != "" {
-						locationTypeCounter[session.LocationDetails.Type].TotalMood += *session.Mood
-					}
-				}
-			}
-
-			if session.Distractions != nil {
-				totalDistract += *session.Distractions
-				distractCount++
-
-				// Add to counters
-				dayCounter[day].TotalDistract += *session.Distractions
-				timeCounter[tod].TotalDistract += *session.Distractions
-
-				if session.LocationDetails != nil {
-					locationCounter[session.LocationDetails.Name].TotalDistract += *session.Distractions
-					if session.LocationDetails.Type != "" {
-						locationTypeCounter[session.LocationDetails.Type].TotalDistract += *session.Distractions
-					}
-				}
-			}
-		} else if session.Status == entity.StatusCancelled {
-			cancelledCount++
-		}
+	if result.MatchedCount == 0 {
+		return errors.New("session is not active")
 	}
 
-	// Set overall stats
-	stats.CompletedSessions = completedCount
-	stats.CancelledSessions = cancelledCount
-	stats.TotalDuration = totalDuration
-
-	// Calculate averages
-	if ratingCount > 0 {
-		stats.AverageRating = float64(totalRating) / float64(ratingCount)
-	}
+	return nil
+}
 
-	if focusCount > 0 {
-		stats.AverageFocus = float64(totalFocus) / float64(focusCount)
-	}
+func (r *mongoFocusSessionRepository) ResumeSession(ctx context.Context, id primitive.ObjectID, resumedAt time.Time) error {
+	// Close the open pause interval, which is the only one without resumedAt
+	updateOptions := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{
+			bson.M{"pause.resumedAt": bson.M{"$exists": false}},
+		},
+	})
 
-	if energyCount > 0 {
-		stats.AverageEnergy = float64(totalEnergy) / float64(energyCount)
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": entity.StatusPaused},
+		bson.M{
+			"$set": bson.M{
+				"status":                    entity.StatusActive,
+				"pauses.$[pause].resumedAt": resumedAt,
+				"updatedAt":                 time.Now(),
+			},
+		},
+		updateOptions,
+	)
+	if err != nil {
+		return err
 	}
 
-	if moodCount > 0 {
-		stats.AverageMood = float64(totalMood) / float64(moodCount)
+	if result.MatchedCount == 0 {
+		return errors.New("session is not paused")
 	}
 
-	if distractCount > 0 {
-		stats.AverageDistractions = float64(totalDistract) / float64(distractCount)
-	}
+	return nil
+}
 
-	// Calculate productivity by day
-	var maxDayScore float64
-	for day, counter := range dayCounter {
-		if counter.Count > 0 {
-			// Average productivity score for this day
-			avgScore := counter.TotalScore / float64(counter.Count)
-			stats.ProductivityByDay[day] = avgScore
+func (r *mongoFocusSessionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{
+				"active": false,
+			},
+		})
+	return err
+}
 
-			if avgScore > maxDayScore {
-				maxDayScore = avgScore
-				stats.MostProductiveDay = day
-			}
+// GetProductivityStats aggregates the sessions between startDate and endDate
+// in the database, reading days and times of day in loc. Scorers without an
+// aggregation expression are applied in Go.
+func (r *mongoFocusSessionRepository) GetProductivityStats(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	startDate, endDate time.Time,
+	loc *time.Location,
+	scorer entity.ProductivityScorer,
+) (*entity.ProductivityStats, error) {
+	score, ok := scoreExpression(scorer)
+	if !ok {
+		sessions, err := r.GetSessionsByDateRange(ctx, userID, startDate, endDate)
+		if err != nil {
+			return nil, err
 		}
+		return entity.ComputeProductivityStats(sessions, loc, scorer), nil
 	}
 
-	// Calculate productivity by time of day
-	var maxTimeScore float64
-	for tod, counter := range timeCounter {
-		if counter.Count > 0 {
-			// Average productivity score for this time of day
-			avgScore := counter.TotalScore / float64(counter.Count)
-			stats.ProductivityByTime[tod] = avgScore
-
-			if avgScore > maxTimeScore {
-				maxTimeScore = avgScore
-				stats.MostProductiveTime = tod
-			}
-		}
+	cursor, err := r.collection.Aggregate(ctx, productivityStatsPipeline(userID, startDate, endDate, loc, score))
+	if err != nil {
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	// Calculate productivity by location
-	var maxLocationScore float64
-	for loc, counter := range locationCounter {
-		if counter.Count > 0 {
-			// Average productivity score for this location
-			avgScore := counter.TotalScore / float64(counter.Count)
-			stats.ProductivityByLocation[loc] = avgScore
-
-			if avgScore > maxLocationScore {
-				maxLocationScore = avgScore
-				stats.MostProductiveLocation = loc
-			}
+	var facets productivityStatsFacets
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&facets); err != nil {
+			return nil, err
 		}
 	}
-
-	// Calculate productivity by location type
-	var maxLocationTypeScore float64
-	for locType, counter := range locationTypeCounter {
-		if counter.Count > 0 {
-			// Average productivity score for this location type
-			avgScore := counter.TotalScore / float64(counter.Count)
-			stats.ProductivityByLocationType[locType] = avgScore
-
-			if avgScore > maxLocationTypeScore {
-				maxLocationTypeScore = avgScore
-				stats.MostProductiveLocationType = locType
-			}
-		}
+	if err := cursor.Err(); err != nil {
+		return nil, err
 	}
 
-	return stats, nil
+	return facets.toStats(), nil
 }
 
-func (r *mongoFocusSessionRepository) GetProductivityTrends(ctx context.Context, userID primitive.ObjectID, period entity.Period) (*entity.ProductivityTrends, error) {
-	// Determine date range based on period
-	endDate := time.Now()
-	var startDate time.Time
-
-	switch period {
-	case entity.Daily:
-		// Last 30 days
-		startDate = endDate.AddDate(0, 0, -30)
-	case entity.Weekly:
-		// Last 12 weeks
-		startDate = endDate.AddDate(0, 0, -12*7)
-	case entity.Monthly:
-		// Last 12 months
-		startDate = endDate.AddDate(-1, 0, 0)
-	default:
-		// Default to weekly
-		startDate = endDate.AddDate(0, 0, -12*7)
-		period = entity.Weekly
+// GetProductivityTrends groups the completed sessions between startDate and
+// endDate into periods in the database, scoring them by scorer. Periods are
+// read in the location of startDate, and every period the window touches is
+// returned.
+func (r *mongoFocusSessionRepository) GetProductivityTrends(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	period entity.Period,
+	startDate, endDate time.Time,
+	scorer entity.ProductivityScorer,
+) (*entity.ProductivityTrends, error) {
+	score, ok := scoreExpression(scorer)
+	if !ok {
+		sessions, err := r.GetSessionsByDateRange(ctx, userID, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		return entity.ComputeProductivityTrends(sessions, period, startDate, endDate, scorer), nil
 	}
 
-	// Get all sessions in date range
-	sessions, err := r.GetSessionsByDateRange(ctx, userID, startDate, endDate)
+	cursor, err := r.collection.Aggregate(ctx, productivityTrendsPipeline(userID, period, startDate, endDate, score))
 	if err != nil {
 		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	// Group sessions by period
-	type PeriodData struct {
-		StartDate         time.Time
-		Sessions          []*entity.FocusSession
-		TotalDuration     int
-		TotalRating       int
-		RatingCount       int
-		TotalFocus        int
-		FocusCount        int
-		TotalEnergy       int
-		EnergyCount       int
-		TotalMood         int
-		MoodCount         int
-		TotalProductivity float64
-		ProductivityCount int
-	}
-
-	// Create a map to group sessions
-	periodMap := make(map[string]*PeriodData)
-
-	// Define format strings for each period type
-	var formatStr string
-	var truncateFunc func(time.Time) time.Time
-
-	switch period {
-	case entity.Daily:
-		formatStr = "2006-01-02"
-		truncateFunc = func(t time.Time) time.Time {
-			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
-		}
-	case entity.Weekly:
-		formatStr = "2006-W%02d" // ISO week format
-		truncateFunc = func(t time.Time) time.Time {
-			year, _ := t.ISOWeek()
-			// Start of ISO week (Monday)
-			// Go to the Monday of this week
-			daysToSubtract := int(t.Weekday())
-			if daysToSubtract == 0 { // Sunday
-				daysToSubtract = 6
-			} else {
-				daysToSubtract--
-			}
-			return time.Date(year, t.Month(), t.Day()-daysToSubtract, 0, 0, 0, 0, t.Location())
-		}
-	case entity.Monthly:
-		formatStr = "2006-01"
-		truncateFunc = func(t time.Time) time.Time {
-			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
-		}
+	var groups []trendGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
 	}
 
-	// Create entries for all periods in range
-	currentDate := truncateFunc(startDate)
-	endTruncated := truncateFunc(endDate)
-
-	for currentDate.Before(endTruncated) || currentDate.Equal(endTruncated) {
-		var periodKey string
+	return toTrends(groups, period, startDate, endDate), nil
+}
 
-		if period == entity.Weekly {
-			year, week := currentDate.ISOWeek()
-			periodKey = fmt.Sprintf(formatStr, year, week)
-		} else {
-			periodKey = currentDate.Format(formatStr)
-		}
+// GetLocationProductivity sums up the user's completed sessions at each of
+// the spots in the database, scoring them by scorer. Spots the user has no
+// completed sessions at are left out.
+func (r *mongoFocusSessionRepository) GetLocationProductivity(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	locationIDs []primitive.ObjectID,
+	scorer entity.ProductivityScorer,
+) ([]*entity.LocationProductivity, error) {
+	if len(locationIDs) == 0 {
+		return []*entity.LocationProductivity{}, nil
+	}
 
-		periodMap[periodKey] = &PeriodData{
-			StartDate: currentDate,
-			Sessions:  make([]*entity.FocusSession, 0),
-		}
+	return r.locationProductivity(ctx, bson.M{
+		"userId":         userID,
+		"locationId":     bson.M{"$in": locationIDs},
+		"status":         entity.StatusCompleted,
+		"actualDuration": bson.M{"$ne": nil},
+		"active":         true,
+	}, scorer)
+}
 
-		// Advance to next period
-		switch period {
-		case entity.Daily:
-			currentDate = currentDate.AddDate(0, 0, 1)
-		case entity.Weekly:
-			currentDate = currentDate.AddDate(0, 0, 7)
-		case "monthly":
-			currentDate = currentDate.AddDate(0, 1, 0)
-		}
+// GetCommunityLocationProductivity sums up the completed sessions of every
+// user but the given one at each of the spots, scoring them by scorer. Users
+// counts how many people the sums are from, so that callers can keep any
+// one person's productivity from showing.
+func (r *mongoFocusSessionRepository) GetCommunityLocationProductivity(
+	ctx context.Context,
+	excludeUserID primitive.ObjectID,
+	locationIDs []primitive.ObjectID,
+	scorer entity.ProductivityScorer,
+) ([]*entity.LocationProductivity, error) {
+	if len(locationIDs) == 0 {
+		return []*entity.LocationProductivity{}, nil
 	}
 
-	// Group sessions into periods
-	for _, session := range sessions {
-		if session.Status != entity.StatusCompleted || session.ActualDuration == nil {
-			continue
-		}
-
-		// Get period key
-		var periodKey string
-		periodStart := truncateFunc(session.StartTime)
+	return r.locationProductivity(ctx, bson.M{
+		"locationId":     bson.M{"$in": locationIDs},
+		"userId":         bson.M{"$ne": excludeUserID},
+		"status":         entity.StatusCompleted,
+		"actualDuration": bson.M{"$ne": nil},
+		"active":         true,
+	}, scorer)
+}
 
-		if period == entity.Weekly {
-			year, week := periodStart.ISOWeek()
-			periodKey = fmt.Sprintf(formatStr, year, week)
-		} else {
-			periodKey = periodStart.Format(formatStr)
+// locationProductivity groups the completed sessions matching filter by spot
+func (r *mongoFocusSessionRepository) locationProductivity(
+	ctx context.Context,
+	filter bson.M,
+	scorer entity.ProductivityScorer,
+) ([]*entity.LocationProductivity, error) {
+	score, ok := scoreExpression(scorer)
+	if !ok {
+		cursor, err := r.collection.Find(ctx, filter)
+		if err != nil {
+			return nil, err
 		}
+		defer cursor.Close(ctx)
 
-		// Add session to period
-		if data, exists := periodMap[periodKey]; exists {
-			data.Sessions = append(data.Sessions, session)
-
-			// Accumulate metrics
-			data.TotalDuration += *session.ActualDuration
-
-			if session.Rating != nil {
-				data.TotalRating += *session.Rating
-				data.RatingCount++
-			}
-
-			if session.Focus != nil {
-				data.TotalFocus += *session.Focus
-				data.FocusCount++
-			}
-
-			if session.Energy != nil {
-				data.TotalEnergy += *session.Energy
-				data.EnergyCount++
-			}
-
-			if session.Mood != nil {
-				data.TotalMood += *session.Mood
-				data.MoodCount++
-			}
-
-			// Calculate productivity score
-			prodScore := session.CalculateProductivityScore()
-			if prodScore > 0 {
-				data.TotalProductivity += prodScore
-				data.ProductivityCount++
-			}
+		var sessions []*entity.FocusSession
+		if err := cursor.All(ctx, &sessions); err != nil {
+			return nil, err
 		}
+		return entity.ComputeLocationProductivity(sessions, scorer), nil
 	}
 
-	// Sort periods by date
-	periodKeys := make([]string, 0, len(periodMap))
-	for key := range periodMap {
-		periodKeys = append(periodKeys, key)
+	// Sum up each user at each spot first, to count the users at the spot
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       bson.M{"locationId": "$locationId", "userId": "$userId"},
+			"sessions":  bson.M{"$sum": 1},
+			"minutes":   bson.M{"$sum": "$actualDuration"},
+			"score":     bson.M{"$sum": score},
+			"lastVisit": bson.M{"$max": "$startTime"},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$_id.locationId",
+			"users":     bson.M{"$sum": 1},
+			"sessions":  bson.M{"$sum": "$sessions"},
+			"minutes":   bson.M{"$sum": "$minutes"},
+			"score":     bson.M{"$sum": "$score"},
+			"lastVisit": bson.M{"$max": "$lastVisit"},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"productivity": bson.M{"$divide": bson.A{"$score", "$sessions"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
 	}
 
-	// Sort keys
-	sort.Slice(periodKeys, func(i, j int) bool {
-		return periodMap[periodKeys[i]].StartDate.Before(periodMap[periodKeys[j]].StartDate)
-	})
-
-	// Build result
-	trends := &entity.ProductivityTrends{
-		Period:       period,
-		Dates:        make([]string, 0, len(periodKeys)),
-		Durations:    make([]int, 0, len(periodKeys)),
-		Ratings:      make([]float64, 0, len(periodKeys)),
-		Focus:        make([]float64, 0, len(periodKeys)),
-		Energy:       make([]float64, 0, len(periodKeys)),
-		Mood:         make([]float64, 0, len(periodKeys)),
-		Productivity: make([]float64, 0, len(periodKeys)),
-	}
-
-	for _, key := range periodKeys {
-		data := periodMap[key]
-
-		// Format date display
-		var displayDate string
-		switch period {
-		case entity.Daily:
-			displayDate = data.StartDate.Format("Jan 02")
-		case entity.Weekly:
-			// Format as "Jan 02-08" (start and end of week)
-			weekEnd := data.StartDate.AddDate(0, 0, 6)
-			if data.StartDate.Month() == weekEnd.Month() {
-				displayDate = fmt.Sprintf("%s %d-%d", data.StartDate.Format("Jan"), data.StartDate.Day(), weekEnd.Day())
-			} else {
-				displayDate = fmt.Sprintf("%s-%s", data.StartDate.Format("Jan 02"), weekEnd.Format("Jan 02"))
-			}
-		case entity.Monthly:
-			displayDate = data.StartDate.Format("Jan 2006")
-		}
-
-		trends.Dates = append(trends.Dates, displayDate)
-
-		// Calculate averages
-		if len(data.Sessions) > 0 {
-			trends.Durations = append(trends.Durations, data.TotalDuration)
-		} else {
-			trends.Durations = append(trends.Durations, 0)
-		}
-
-		if data.RatingCount > 0 {
-			trends.Ratings = append(trends.Ratings, float64(data.TotalRating)/float64(data.RatingCount))
-		} else {
-			trends.Ratings = append(trends.Ratings, 0)
-		}
-
-		if data.FocusCount > 0 {
-			trends.Focus = append(trends.Focus, float64(data.TotalFocus)/float64(data.FocusCount))
-		} else {
-			trends.Focus = append(trends.Focus, 0)
-		}
-
-		if data.EnergyCount > 0 {
-			trends.Energy = append(trends.Energy, float64(data.TotalEnergy)/float64(data.EnergyCount))
-		} else {
-			trends.Energy = append(trends.Energy, 0)
-		}
-
-		if data.MoodCount > 0 {
-			trends.Mood = append(trends.Mood, float64(data.TotalMood)/float64(data.MoodCount))
-		} else {
-			trends.Mood = append(trends.Mood, 0)
-		}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
 
-		if data.ProductivityCount > 0 {
-			trends.Productivity = append(trends.Productivity, data.TotalProductivity/float64(data.ProductivityCount))
-		} else {
-			trends.Productivity = append(trends.Productivity, 0)
-		}
+	var productivity []*entity.LocationProductivity
+	if err := cursor.All(ctx, &productivity); err != nil {
+		return nil, err
 	}
 
-	return trends, nil
+	return productivity, nil
 }
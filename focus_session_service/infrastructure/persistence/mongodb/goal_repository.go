@@ -0,0 +1,111 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoGoalRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoGoalRepository(db *mongo.Database) interfaces.IGoalRepository {
+	collection := db.Collection("goals")
+
+	// Create indexes
+	_, err := collection.Indexes().CreateOne(
+		context.Background(),
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoGoalRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoGoalRepository) Create(ctx context.Context, goal *entity.Goal) error {
+	if goal.ID.IsZero() {
+		goal.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, goal)
+
+	return err
+}
+
+func (r *mongoGoalRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*entity.Goal, error) {
+	var goal entity.Goal
+
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "active": true}).Decode(&goal)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("goal not found")
+		}
+		return nil, err
+	}
+
+	return &goal, nil
+}
+
+func (r *mongoGoalRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.Goal, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"userId": userID,
+		"active": true,
+	}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var goals []*entity.Goal
+	if err := cursor.All(ctx, &goals); err != nil {
+		return nil, err
+	}
+
+	return goals, nil
+}
+
+func (r *mongoGoalRepository) Update(ctx context.Context, goal *entity.Goal) error {
+	goal.UpdatedAt = time.Now()
+
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": goal.ID},
+		goal,
+	)
+
+	return err
+}
+
+func (r *mongoGoalRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{
+				"active":    false,
+				"updatedAt": time.Now(),
+			},
+		})
+	return err
+}
@@ -0,0 +1,224 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoLocationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLocationRepository(db *mongo.Database) interfaces.ILocationRepository {
+	collection := db.Collection("locations")
+
+	// Create indexes
+	_, err := collection.Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			{
+				// Spots near a point
+				Keys: bson.D{
+					{Key: "coordinates", Value: "2dsphere"},
+				},
+			},
+			{
+				Keys: bson.D{
+					{Key: "active", Value: 1},
+					{Key: "type", Value: 1},
+					{Key: "name", Value: 1},
+				},
+			},
+			{
+				Keys: bson.D{
+					{Key: "createdBy", Value: 1},
+					{Key: "createdAt", Value: 1},
+				},
+			},
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoLocationRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoLocationRepository) Create(ctx context.Context, location *entity.Location) error {
+	if location.ID.IsZero() {
+		location.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, location)
+
+	return err
+}
+
+// GetByID returns the spot unless it is private to another user than the
+// viewer, in which case it is not found either
+func (r *mongoLocationRepository) GetByID(ctx context.Context, id, viewerID primitive.ObjectID) (*entity.Location, error) {
+	var location entity.Location
+
+	query := visibleQuery(viewerID)
+	query["_id"] = id
+
+	err := r.collection.FindOne(ctx, query).Decode(&location)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("location not found")
+		}
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// GetByIDs returns the active spots among ids the viewer may see, in no
+// particular order
+func (r *mongoLocationRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID, viewerID primitive.ObjectID) ([]*entity.Location, error) {
+	if len(ids) == 0 {
+		return []*entity.Location{}, nil
+	}
+
+	query := visibleQuery(viewerID)
+	query["_id"] = bson.M{"$in": ids}
+
+	cursor, err := r.collection.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var locations []*entity.Location
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+// List returns the spots matching the filter, ordered by name
+func (r *mongoLocationRepository) List(ctx context.Context, filter entity.LocationFilter, limit, offset int) ([]*entity.Location, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(offset))
+
+	cursor, err := r.collection.Find(ctx, locationFilterQuery(filter), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var locations []*entity.Location
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+// GetNearby returns the spots matching the filter within radius meters of
+// point, nearest first
+func (r *mongoLocationRepository) GetNearby(
+	ctx context.Context,
+	point entity.GeoPoint,
+	radius float64,
+	filter entity.LocationFilter,
+	limit int,
+) ([]*entity.NearbyLocation, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":          point,
+			"key":           "coordinates",
+			"distanceField": "distance",
+			"maxDistance":   radius,
+			"spherical":     true,
+			"query":         locationFilterQuery(filter),
+		}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var locations []*entity.NearbyLocation
+	if err := cursor.All(ctx, &locations); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+func (r *mongoLocationRepository) Update(ctx context.Context, location *entity.Location) error {
+	location.UpdatedAt = time.Now()
+
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": location.ID},
+		location,
+	)
+
+	return err
+}
+
+func (r *mongoLocationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{
+				"active":    false,
+				"updatedAt": time.Now(),
+			},
+		})
+	return err
+}
+
+// locationFilterQuery builds the query for the active spots matching the
+// filter that the viewer may see
+func locationFilterQuery(filter entity.LocationFilter) bson.M {
+	query := visibleQuery(filter.Viewer)
+
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Name != "" {
+		query["name"] = primitive.Regex{Pattern: regexp.QuoteMeta(filter.Name), Options: "i"}
+	}
+	if len(filter.Amenities) > 0 {
+		query["amenities"] = bson.M{"$all": filter.Amenities}
+	}
+	if filter.CreatedBy != nil {
+		query["createdBy"] = *filter.CreatedBy
+	}
+
+	return query
+}
+
+// visibleQuery matches the active spots the viewer may see: every public spot
+// and their own private ones
+func visibleQuery(viewerID primitive.ObjectID) bson.M {
+	return bson.M{
+		"active": true,
+		"$or": bson.A{
+			bson.M{"private": bson.M{"$ne": true}},
+			bson.M{"createdBy": viewerID},
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoLocationReviewRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLocationReviewRepository(db *mongo.Database) interfaces.ILocationReviewRepository {
+	collection := db.Collection("location_reviews")
+
+	// Create indexes
+	_, err := collection.Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			{
+				// One review per user and spot
+				Keys: bson.D{
+					{Key: "locationId", Value: 1}, {Key: "userId", Value: 1},
+				},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys: bson.D{
+					{Key: "locationId", Value: 1}, {Key: "updatedAt", Value: -1},
+				},
+			},
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoLocationReviewRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoLocationReviewRepository) GetByUser(ctx context.Context, locationID, userID primitive.ObjectID) (*entity.LocationReview, error) {
+	var review entity.LocationReview
+
+	err := r.collection.FindOne(ctx, bson.M{"locationId": locationID, "userId": userID}).Decode(&review)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // Not reviewed yet, not an error
+		}
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// ListByLocation returns a page of the spot's reviews, most recently updated
+// first
+func (r *mongoLocationReviewRepository) ListByLocation(ctx context.Context, locationID primitive.ObjectID, limit, offset int) ([]*entity.LocationReview, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: 1}})
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSkip(int64(offset))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"locationId": locationID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []*entity.LocationReview
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Upsert stores the user's review of a spot, replacing any earlier one
+func (r *mongoLocationReviewRepository) Upsert(ctx context.Context, review *entity.LocationReview) error {
+	if review.ID.IsZero() {
+		review.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"locationId": review.LocationID, "userId": review.UserID},
+		review,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
+
+func (r *mongoLocationReviewRepository) Delete(ctx context.Context, locationID, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"locationId": locationID, "userId": userID})
+	return err
+}
+
+// GetRatings averages the reviews of each of the spots. Spots without
+// reviews are left out.
+func (r *mongoLocationReviewRepository) GetRatings(ctx context.Context, locationIDs []primitive.ObjectID) ([]*entity.LocationRatings, error) {
+	if len(locationIDs) == 0 {
+		return []*entity.LocationRatings{}, nil
+	}
+
+	group := bson.M{
+		"_id":     "$locationId",
+		"reviews": bson.M{"$sum": 1},
+	}
+	// $avg skips the reviews that leave an aspect unrated
+	for _, aspect := range entity.ReviewAspects {
+		group[string(aspect)] = bson.M{"$avg": "$" + string(aspect)}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"locationId": bson.M{"$in": locationIDs}}}},
+		{{Key: "$group", Value: group}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ratings []*entity.LocationRatings
+	if err := cursor.All(ctx, &ratings); err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
@@ -0,0 +1,417 @@
+package mongodb
+
+import (
+	"focusspot/focussessionservice/domain/entity"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// scoreExpression translates a built-in scorer into an aggregation
+// expression giving the same score as its Score method. Other scorers have
+// none, and their sessions are scored in Go.
+func scoreExpression(scorer entity.ProductivityScorer) (bson.M, bool) {
+	switch s := scorer.(type) {
+	case entity.ClassicScorer:
+		return classicScoreExpression(), true
+	case entity.WeightedScorer:
+		return weightedScoreExpression(s.Weights), true
+	case entity.DurationNormalizedScorer:
+		return durationNormalizedScoreExpression(), true
+	}
+	return nil, false
+}
+
+// Mirrors FocusSession.CalculateProductivityScore
+func classicScoreExpression() bson.M {
+	durationAdjustment := cond(
+		bson.M{"$gt": bson.A{"$duration", 0}},
+		cond(bson.M{"$gt": bson.A{completionRatio(), 1.2}}, 1.0, -1.0),
+		0,
+	)
+
+	return cond(
+		bson.M{"$and": bson.A{isScored(), isSet("$rating")}},
+		clamp(bson.M{"$subtract": bson.A{
+			bson.M{"$add": bson.A{"$rating", durationAdjustment, focusBonus()}},
+			distractionPenalty(),
+		}}, 0, 10),
+		0,
+	)
+}
+
+// Mirrors WeightedScorer.Score
+func weightedScoreExpression(weights entity.MetricWeights) bson.M {
+	parts := []struct {
+		field  string
+		value  interface{}
+		weight float64
+	}{
+		{"$rating", scaled("$rating", 1, 5), weights.Rating},
+		{"$focus", scaled("$focus", 1, 10), weights.Focus},
+		{"$energy", scaled("$energy", 1, 10), weights.Energy},
+		{"$mood", scaled("$mood", 1, 10), weights.Mood},
+		{"$distractions", bson.M{"$subtract": bson.A{
+			1,
+			bson.M{"$min": bson.A{bson.M{"$divide": bson.A{"$distractions", 10.0}}, 1.0}},
+		}}, weights.Distractions},
+	}
+
+	total := bson.A{}
+	sum := bson.A{}
+	for _, part := range parts {
+		total = append(total, cond(isSet(part.field), bson.M{"$multiply": bson.A{part.value, part.weight}}, 0))
+		sum = append(sum, cond(isSet(part.field), part.weight, 0))
+	}
+
+	return bson.M{"$let": bson.M{
+		"vars": bson.M{
+			"total":   bson.M{"$add": total},
+			"weights": bson.M{"$add": sum},
+		},
+		"in": cond(
+			bson.M{"$and": bson.A{isScored(), bson.M{"$ne": bson.A{"$$weights", 0}}}},
+			bson.M{"$multiply": bson.A{bson.M{"$divide": bson.A{"$$total", "$$weights"}}, 10}},
+			0,
+		),
+	}}
+}
+
+// Mirrors DurationNormalizedScorer.Score
+func durationNormalizedScoreExpression() bson.M {
+	durationAdjustment := cond(
+		bson.M{"$gt": bson.A{"$duration", 0}},
+		clamp(bson.M{"$multiply": bson.A{bson.M{"$subtract": bson.A{completionRatio(), 1}}, 2}}, -1, 1),
+		0,
+	)
+
+	return cond(
+		bson.M{"$and": bson.A{isScored(), isSet("$rating")}},
+		clamp(bson.M{"$subtract": bson.A{
+			bson.M{"$add": bson.A{"$rating", durationAdjustment, focusBonus()}},
+			distractionPenalty(),
+		}}, 0, 10),
+		0,
+	)
+}
+
+// isScored is true for completed sessions with an actual duration
+func isScored() bson.M {
+	return bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$status", entity.StatusCompleted}},
+		isSet("$actualDuration"),
+	}}
+}
+
+// isSet is true when the field holds a number. Missing fields and null sort
+// below numbers.
+func isSet(field string) bson.M {
+	return bson.M{"$gt": bson.A{field, nil}}
+}
+
+func cond(condition, then, otherwise interface{}) bson.M {
+	return bson.M{"$cond": bson.A{condition, then, otherwise}}
+}
+
+func clamp(value interface{}, low, high float64) bson.M {
+	return bson.M{"$max": bson.A{low, bson.M{"$min": bson.A{high, value}}}}
+}
+
+// scaled maps a field in [min, max] to [0, 1], like the scorers' scale
+func scaled(field string, min, max int) bson.M {
+	return clamp(bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{field, min}}, max - min}}, 0, 1)
+}
+
+func completionRatio() bson.M {
+	return bson.M{"$divide": bson.A{"$actualDuration", "$duration"}}
+}
+
+// focusBonus gives 0-2 points for focus, if set
+func focusBonus() bson.M {
+	return cond(isSet("$focus"), bson.M{"$multiply": bson.A{bson.M{"$divide": bson.A{"$focus", 10.0}}, 2.0}}, 0)
+}
+
+// distractionPenalty takes up to a point for distractions
+func distractionPenalty() bson.M {
+	return cond(
+		bson.M{"$gt": bson.A{"$distractions", 0}},
+		bson.M{"$min": bson.A{bson.M{"$divide": bson.A{"$distractions", 10.0}}, 1.0}},
+		0,
+	)
+}
+
+// timeOfDayExpression mirrors entity.GetTimeOfDay on the hour field
+func timeOfDayExpression(hour string) bson.M {
+	branch := func(from, to int, tod entity.TimeOfDay) bson.M {
+		return bson.M{
+			"case": bson.M{"$and": bson.A{
+				bson.M{"$gte": bson.A{hour, from}},
+				bson.M{"$lt": bson.A{hour, to}},
+			}},
+			"then": tod,
+		}
+	}
+
+	return bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			branch(5, 9, entity.EarlyMorning),
+			branch(9, 12, entity.LateMorning),
+			branch(12, 17, entity.Afternoon),
+			branch(17, 21, entity.Evening),
+		},
+		"default": entity.Night,
+	}}
+}
+
+// productivityStatsPipeline computes everything ProductivityStats holds in
+// one pass, with a facet per grouping
+func productivityStatsPipeline(
+	userID primitive.ObjectID,
+	startDate, endDate time.Time,
+	loc *time.Location,
+	score bson.M,
+) mongo.Pipeline {
+	counted := bson.M{"$match": bson.M{"counted": true}}
+
+	// Average score of the counted sessions in each group
+	groupScores := func(key interface{}, stages ...bson.M) bson.A {
+		facet := bson.A{counted}
+		for _, stage := range stages {
+			facet = append(facet, stage)
+		}
+		return append(facet, bson.M{"$group": bson.M{
+			"_id":          key,
+			"productivity": bson.M{"$avg": "$score"},
+		}})
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"userId": userID,
+			"startTime": bson.M{
+				"$gte": startDate,
+				"$lte": endDate,
+			},
+			"status": bson.M{"$in": []entity.SessionStatus{entity.StatusCompleted, entity.StatusCancelled}},
+			"active": true,
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"counted": isScored(),
+			"score":   score,
+			"day":     bson.M{"$dayOfWeek": bson.M{"date": "$startTime", "timezone": loc.String()}},
+			"hour":    bson.M{"$hour": bson.M{"date": "$startTime", "timezone": loc.String()}},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"timeOfDay": timeOfDayExpression("$hour"),
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"sessions": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   nil,
+					"total": bson.M{"$sum": 1},
+					"cancelled": bson.M{"$sum": cond(
+						bson.M{"$eq": bson.A{"$status", entity.StatusCancelled}}, 1, 0,
+					)},
+				}},
+			},
+			"completed": bson.A{
+				counted,
+				bson.M{"$group": bson.M{
+					"_id":          nil,
+					"count":        bson.M{"$sum": 1},
+					"duration":     bson.M{"$sum": "$actualDuration"},
+					"pomodoros":    bson.M{"$sum": bson.M{"$ifNull": bson.A{"$pomodoro.completedPomodoros", 0}}},
+					"productivity": bson.M{"$avg": "$score"},
+					"rating":       bson.M{"$avg": "$rating"},
+					"focus":        bson.M{"$avg": "$focus"},
+					"energy":       bson.M{"$avg": "$energy"},
+					"mood":         bson.M{"$avg": "$mood"},
+					"distractions": bson.M{"$avg": "$distractions"},
+					"distractionSessions": bson.M{"$sum": bson.M{"$cond": bson.A{
+						bson.M{"$isNumber": "$distractions"}, 1, 0,
+					}}},
+				}},
+			},
+			"byDay":  groupScores("$day"),
+			"byTime": groupScores("$timeOfDay"),
+			"byLocation": groupScores(
+				bson.M{"$ifNull": bson.A{"$locationDetails.name", ""}},
+				bson.M{"$match": bson.M{"locationDetails": bson.M{"$ne": nil}}},
+			),
+			"byLocationType": groupScores(
+				"$locationDetails.type",
+				bson.M{"$match": bson.M{"locationDetails.type": bson.M{"$nin": bson.A{nil, ""}}}},
+			),
+			"byTag": groupScores("$tags", bson.M{"$unwind": "$tags"}),
+		}}},
+	}
+}
+
+// productivityStatsFacets is the one document productivityStatsPipeline
+// returns
+type productivityStatsFacets struct {
+	Sessions []struct {
+		Total     int `bson:"total"`
+		Cancelled int `bson:"cancelled"`
+	} `bson:"sessions"`
+	Completed []struct {
+		Count               int     `bson:"count"`
+		Duration            int     `bson:"duration"`
+		Pomodoros           int     `bson:"pomodoros"`
+		Productivity        float64 `bson:"productivity"`
+		Rating              float64 `bson:"rating"`
+		Focus               float64 `bson:"focus"`
+		Energy              float64 `bson:"energy"`
+		Mood                float64 `bson:"mood"`
+		Distractions        float64 `bson:"distractions"`
+		DistractionSessions int     `bson:"distractionSessions"`
+	} `bson:"completed"`
+	ByDay []struct {
+		Day          int     `bson:"_id"` // 1 is Sunday
+		Productivity float64 `bson:"productivity"`
+	} `bson:"byDay"`
+	ByTime         []productivityGroup `bson:"byTime"`
+	ByLocation     []productivityGroup `bson:"byLocation"`
+	ByLocationType []productivityGroup `bson:"byLocationType"`
+	ByTag          []productivityGroup `bson:"byTag"`
+}
+
+type productivityGroup struct {
+	Key          string  `bson:"_id"`
+	Productivity float64 `bson:"productivity"`
+}
+
+func (f *productivityStatsFacets) toStats() *entity.ProductivityStats {
+	stats := entity.NewProductivityStats()
+
+	if len(f.Sessions) > 0 {
+		stats.TotalSessions = f.Sessions[0].Total
+		stats.CancelledSessions = f.Sessions[0].Cancelled
+	}
+
+	if len(f.Completed) > 0 {
+		completed := f.Completed[0]
+		stats.CompletedSessions = completed.Count
+		stats.TotalDuration = completed.Duration
+		stats.PomodorosCompleted = completed.Pomodoros
+		stats.AverageProductivity = completed.Productivity
+		stats.AverageRating = completed.Rating
+		stats.AverageFocus = completed.Focus
+		stats.AverageEnergy = completed.Energy
+		stats.AverageMood = completed.Mood
+		stats.AverageDistractions = completed.Distractions
+		stats.DistractionSessions = completed.DistractionSessions
+	}
+
+	for _, group := range f.ByDay {
+		stats.ProductivityByDay[time.Weekday(group.Day-1)] = group.Productivity
+	}
+	for _, group := range f.ByTime {
+		stats.ProductivityByTime[entity.TimeOfDay(group.Key)] = group.Productivity
+	}
+	for _, group := range f.ByLocation {
+		stats.ProductivityByLocation[group.Key] = group.Productivity
+	}
+	for _, group := range f.ByLocationType {
+		stats.ProductivityByLocationType[group.Key] = group.Productivity
+	}
+	for _, group := range f.ByTag {
+		stats.ProductivityByTag[group.Key] = group.Productivity
+	}
+
+	stats.SetMostProductive()
+	return stats
+}
+
+// trendUnits are the $dateTrunc units of the trend periods
+var trendUnits = map[entity.Period]string{
+	entity.Daily:     "day",
+	entity.Weekly:    "week",
+	entity.Monthly:   "month",
+	entity.Quarterly: "quarter",
+	entity.Yearly:    "year",
+}
+
+// productivityTrendsPipeline groups the completed sessions of the window by
+// the start of their period. Periods without sessions are not returned.
+func productivityTrendsPipeline(
+	userID primitive.ObjectID,
+	period entity.Period,
+	startDate, endDate time.Time,
+	score bson.M,
+) mongo.Pipeline {
+	productivity := bson.M{"$let": bson.M{
+		"vars": bson.M{"score": score},
+		"in":   cond(bson.M{"$gt": bson.A{"$$score", 0}}, "$$score", nil),
+	}}
+
+	truncate := bson.M{
+		"date":     "$startTime",
+		"unit":     trendUnits[period],
+		"timezone": startDate.Location().String(),
+	}
+	if period == entity.Weekly {
+		truncate["startOfWeek"] = "monday"
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"userId": userID,
+			"startTime": bson.M{
+				"$gte": startDate,
+				"$lte": endDate,
+			},
+			"status":         entity.StatusCompleted,
+			"actualDuration": bson.M{"$ne": nil},
+			"active":         true,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"$dateTrunc": truncate},
+			"sessions": bson.M{"$sum": 1},
+			"duration": bson.M{"$sum": "$actualDuration"},
+			"rating":   bson.M{"$avg": "$rating"},
+			"focus":    bson.M{"$avg": "$focus"},
+			"energy":   bson.M{"$avg": "$energy"},
+			"mood":     bson.M{"$avg": "$mood"},
+
+			// Sessions without a score do not count towards productivity
+			"productivity": bson.M{"$avg": productivity},
+		}}},
+	}
+}
+
+// trendGroup is one period productivityTrendsPipeline returns
+type trendGroup struct {
+	Start        time.Time `bson:"_id"`
+	Sessions     int       `bson:"sessions"`
+	Duration     int       `bson:"duration"`
+	Rating       float64   `bson:"rating"`
+	Focus        float64   `bson:"focus"`
+	Energy       float64   `bson:"energy"`
+	Mood         float64   `bson:"mood"`
+	Productivity float64   `bson:"productivity"`
+}
+
+// toTrends lays the groups out over every period of the window
+func toTrends(groups []trendGroup, period entity.Period, startDate, endDate time.Time) *entity.ProductivityTrends {
+	points := make(map[int64]entity.TrendPoint, len(groups))
+	for _, group := range groups {
+		points[group.Start.Unix()] = entity.TrendPoint{
+			Sessions:     group.Sessions,
+			Duration:     group.Duration,
+			Rating:       group.Rating,
+			Focus:        group.Focus,
+			Energy:       group.Energy,
+			Mood:         group.Mood,
+			Productivity: group.Productivity,
+		}
+	}
+
+	trends := entity.NewProductivityTrends(period)
+	for _, start := range period.Starts(startDate, endDate) {
+		trends.Add(start, points[start.Unix()])
+	}
+	return trends
+}
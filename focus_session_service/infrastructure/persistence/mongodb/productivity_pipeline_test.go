@@ -0,0 +1,248 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+	"time"
+	_ "time/tzdata" // CI images may have no zoneinfo
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testMongoURIEnv names the MongoDB the equivalence test runs against. The
+// test is skipped without it, and never touches a database it did not create.
+const testMongoURIEnv = "FOCUSSPOT_TEST_MONGODB_URI"
+
+// Values closer than this are taken as equal. The database adds up scores in
+// another order than Go does.
+const tolerance = 1e-9
+
+// Fixture sessions loaded into the scratch database
+const fixtureSessions = 2000
+
+var timezones = []string{"UTC", "Asia/Ho_Chi_Minh", "America/New_York", "Australia/Lord_Howe"}
+
+var periods = []entity.Period{entity.Daily, entity.Weekly, entity.Monthly, entity.Quarterly, entity.Yearly}
+
+// TestProductivityPipelinesMatchReference checks that the productivity stats
+// and trends the database aggregates agree with the in-memory reference, for
+// every scorer over several timezones, windows and periods
+func TestProductivityPipelinesMatchReference(t *testing.T) {
+	db := scratchDatabase(t)
+	ctx := context.Background()
+
+	sessionRepo := NewMongoFocusSessionRepository(db)
+
+	// The fixtures end in the last full year, so that windows are stable
+	now := time.Date(time.Now().Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	userID := primitive.NewObjectID()
+	sessions := generateSessions(rand.New(rand.NewSource(1)), userID, now, fixtureSessions)
+	for _, session := range sessions {
+		if err := sessionRepo.Create(ctx, session); err != nil {
+			t.Fatalf("Failed to load fixtures: %v", err)
+		}
+	}
+
+	checker := &checker{t: t, ctx: ctx, sessionRepo: sessionRepo, userID: userID}
+	for _, name := range timezones {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatalf("Failed to load timezone %s: %v", name, err)
+		}
+		end := now.In(loc).Add(-time.Second)
+
+		for _, scorerName := range entity.ScorerNames() {
+			scorer, _ := entity.ScorerByName(scorerName)
+
+			checker.stats(loc, scorer, end.AddDate(0, 0, -30), end)
+			checker.stats(loc, scorer, end.AddDate(-2, 0, 0), end)
+
+			for _, period := range periods {
+				start := period.Add(period.Start(end), -11)
+				checker.trends(period, scorer, start, end)
+			}
+		}
+	}
+}
+
+// scratchDatabase connects to the test MongoDB and returns a database of its
+// own with a unique name, dropped when the test ends
+func scratchDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv(testMongoURIEnv)
+	if uri == "" {
+		t.Skipf("%s is not set", testMongoURIEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+
+	db := client.Database("focusspot_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		if err := db.Drop(context.Background()); err != nil {
+			t.Errorf("Failed to drop %s: %v", db.Name(), err)
+		}
+		CloseMongoDBConnection(client)
+	})
+
+	return db
+}
+
+type checker struct {
+	t           *testing.T
+	ctx         context.Context
+	sessionRepo interfaces.IFocusSessionRepository
+	userID      primitive.ObjectID
+}
+
+func (c *checker) stats(loc *time.Location, scorer entity.ProductivityScorer, start, end time.Time) {
+	start, end = start.In(loc), end.In(loc)
+	label := fmt.Sprintf("stats %s %s %s..%s", loc, scorer.Version(), start.Format(time.DateOnly), end.Format(time.DateOnly))
+
+	got, err := c.sessionRepo.GetProductivityStats(c.ctx, c.userID, start, end, loc, scorer)
+	if err != nil {
+		c.t.Fatalf("%s: %v", label, err)
+	}
+
+	sessions, err := c.sessionRepo.GetSessionsByDateRange(c.ctx, c.userID, start, end)
+	if err != nil {
+		c.t.Fatalf("%s: %v", label, err)
+	}
+	want := entity.ComputeProductivityStats(sessions, loc, scorer)
+
+	c.compareInts(label, "totalSessions", want.TotalSessions, got.TotalSessions)
+	c.compareInts(label, "completedSessions", want.CompletedSessions, got.CompletedSessions)
+	c.compareInts(label, "cancelledSessions", want.CancelledSessions, got.CancelledSessions)
+	c.compareInts(label, "totalDuration", want.TotalDuration, got.TotalDuration)
+	c.compareInts(label, "pomodorosCompleted", want.PomodorosCompleted, got.PomodorosCompleted)
+	c.compareFloats(label, "averageProductivity", want.AverageProductivity, got.AverageProductivity)
+	c.compareFloats(label, "averageRating", want.AverageRating, got.AverageRating)
+	c.compareFloats(label, "averageFocus", want.AverageFocus, got.AverageFocus)
+	c.compareFloats(label, "averageEnergy", want.AverageEnergy, got.AverageEnergy)
+	c.compareFloats(label, "averageMood", want.AverageMood, got.AverageMood)
+	c.compareFloats(label, "averageDistractions", want.AverageDistractions, got.AverageDistractions)
+	c.compareInts(label, "distractionSessions", want.DistractionSessions, got.DistractionSessions)
+
+	c.compareMaps(label, "productivityByDay", weekdayKeys(want.ProductivityByDay), weekdayKeys(got.ProductivityByDay))
+	c.compareMaps(label, "productivityByTime", timeOfDayKeys(want.ProductivityByTime), timeOfDayKeys(got.ProductivityByTime))
+	c.compareMaps(label, "productivityByLocation", want.ProductivityByLocation, got.ProductivityByLocation)
+	c.compareMaps(label, "productivityByLocationType", want.ProductivityByLocationType, got.ProductivityByLocationType)
+	c.compareMaps(label, "productivityByTag", want.ProductivityByTag, got.ProductivityByTag)
+
+	// The best of nearly equal scores may differ in the last bits, so the
+	// picks are compared by their scores
+	c.compareFloats(label, "mostProductiveDay", want.ProductivityByDay[want.MostProductiveDay], got.ProductivityByDay[got.MostProductiveDay])
+	c.compareFloats(label, "mostProductiveTime", want.ProductivityByTime[want.MostProductiveTime], got.ProductivityByTime[got.MostProductiveTime])
+	c.compareFloats(label, "mostProductiveLocation", want.ProductivityByLocation[want.MostProductiveLocation], got.ProductivityByLocation[got.MostProductiveLocation])
+	c.compareFloats(label, "mostProductiveLocationType", want.ProductivityByLocationType[want.MostProductiveLocationType], got.ProductivityByLocationType[got.MostProductiveLocationType])
+	c.compareFloats(label, "mostProductiveTag", want.ProductivityByTag[want.MostProductiveTag], got.ProductivityByTag[got.MostProductiveTag])
+}
+
+func (c *checker) trends(period entity.Period, scorer entity.ProductivityScorer, start, end time.Time) {
+	label := fmt.Sprintf("trends %s %s %s %s..%s", start.Location(), period, scorer.Version(), start.Format(time.DateOnly), end.Format(time.DateOnly))
+
+	got, err := c.sessionRepo.GetProductivityTrends(c.ctx, c.userID, period, start, end, scorer)
+	if err != nil {
+		c.t.Fatalf("%s: %v", label, err)
+	}
+
+	sessions, err := c.sessionRepo.GetSessionsByDateRange(c.ctx, c.userID, start, end)
+	if err != nil {
+		c.t.Fatalf("%s: %v", label, err)
+	}
+	want := entity.ComputeProductivityTrends(sessions, period, start, end, scorer)
+
+	if len(want.Starts) != len(got.Starts) {
+		c.differ(label, "periods", len(want.Starts), len(got.Starts))
+		return
+	}
+
+	for i := range want.Starts {
+		at := fmt.Sprintf("%s[%s]", label, want.Dates[i])
+		if !want.Starts[i].Equal(got.Starts[i]) {
+			c.differ(at, "start", want.Starts[i], got.Starts[i])
+		}
+		c.compareInts(at, "sessions", want.Sessions[i], got.Sessions[i])
+		c.compareInts(at, "duration", want.Durations[i], got.Durations[i])
+		c.compareFloats(at, "rating", want.Ratings[i], got.Ratings[i])
+		c.compareFloats(at, "focus", want.Focus[i], got.Focus[i])
+		c.compareFloats(at, "energy", want.Energy[i], got.Energy[i])
+		c.compareFloats(at, "mood", want.Mood[i], got.Mood[i])
+		c.compareFloats(at, "productivity", want.Productivity[i], got.Productivity[i])
+	}
+}
+
+func (c *checker) compareInts(label, field string, want, got int) {
+	if want != got {
+		c.differ(label, field, want, got)
+	}
+}
+
+func (c *checker) compareFloats(label, field string, want, got float64) {
+	if math.Abs(want-got) > tolerance {
+		c.differ(label, field, want, got)
+	}
+}
+
+func (c *checker) compareMaps(label, field string, want, got map[string]float64) {
+	keys := make(map[string]bool)
+	for key := range want {
+		keys[key] = true
+	}
+	for key := range got {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		wantScore, inWant := want[key]
+		gotScore, inGot := got[key]
+		if inWant != inGot || math.Abs(wantScore-gotScore) > tolerance {
+			c.differ(label, fmt.Sprintf("%s[%q]", field, key), want[key], got[key])
+		}
+	}
+}
+
+func (c *checker) differ(label, field string, want, got interface{}) {
+	c.t.Helper()
+	c.t.Errorf("%s: %s: in memory %v, database %v", label, field, want, got)
+}
+
+func weekdayKeys(scores map[time.Weekday]float64) map[string]float64 {
+	keyed := make(map[string]float64, len(scores))
+	for day, score := range scores {
+		keyed[day.String()] = score
+	}
+	return keyed
+}
+
+func timeOfDayKeys(scores map[entity.TimeOfDay]float64) map[string]float64 {
+	keyed := make(map[string]float64, len(scores))
+	for tod, score := range scores {
+		keyed[string(tod)] = score
+	}
+	return keyed
+}
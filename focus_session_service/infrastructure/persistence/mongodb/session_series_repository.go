@@ -0,0 +1,154 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSessionSeriesRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSessionSeriesRepository(db *mongo.Database) interfaces.ISessionSeriesRepository {
+	collection := db.Collection("session_series")
+
+	// Create indexes
+	_, err := collection.Indexes().CreateOne(
+		context.Background(),
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "startTime", Value: -1},
+			},
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoSessionSeriesRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoSessionSeriesRepository) Create(ctx context.Context, series *entity.SessionSeries) error {
+	if series.ID.IsZero() {
+		series.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, series)
+
+	return err
+}
+
+func (r *mongoSessionSeriesRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*entity.SessionSeries, error) {
+	var series entity.SessionSeries
+
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "active": true}).Decode(&series)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("series not found")
+		}
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (r *mongoSessionSeriesRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.SessionSeries, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "startTime", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"userId": userID,
+		"active": true,
+	}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var series []*entity.SessionSeries
+	if err := cursor.All(ctx, &series); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (r *mongoSessionSeriesRepository) Update(ctx context.Context, series *entity.SessionSeries) error {
+	series.UpdatedAt = time.Now()
+
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"_id": series.ID},
+		series,
+	)
+
+	return err
+}
+
+func (r *mongoSessionSeriesRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{
+				"active":    false,
+				"updatedAt": time.Now(),
+			},
+		})
+	return err
+}
+
+// ReplaceTag renames a tag on all of the user's series templates, as
+// ReplaceTag does on sessions
+func (r *mongoSessionSeriesRepository) ReplaceTag(ctx context.Context, userID primitive.ObjectID, from, to string) error {
+	now := time.Now()
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "tags": bson.M{"$all": bson.A{from, to}}},
+		bson.M{
+			"$pull": bson.M{"tags": from},
+			"$set":  bson.M{"updatedAt": now},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "tags": from},
+		bson.M{"$set": bson.M{
+			"tags.$[tag]": to,
+			"updatedAt":   now,
+		}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []interface{}{bson.M{"tag": from}},
+		}),
+	)
+
+	return err
+}
+
+// RemoveTag removes a tag from all of the user's series templates
+func (r *mongoSessionSeriesRepository) RemoveTag(ctx context.Context, userID primitive.ObjectID, name string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"userId": userID, "tags": name},
+		bson.M{
+			"$pull": bson.M{"tags": name},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+
+	return err
+}
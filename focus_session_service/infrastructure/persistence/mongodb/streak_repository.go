@@ -0,0 +1,82 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoStreakRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoStreakRepository(db *mongo.Database) interfaces.IStreakRepository {
+	collection := db.Collection("streaks")
+
+	// One streak per user
+	_, err := collection.Indexes().CreateOne(
+		context.Background(),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoStreakRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoStreakRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.Streak, error) {
+	var streak entity.Streak
+
+	err := r.collection.FindOne(ctx, bson.M{"userId": userID}).Decode(&streak)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &streak, nil
+}
+
+func (r *mongoStreakRepository) Save(ctx context.Context, streak *entity.Streak) error {
+	if streak.ID.IsZero() {
+		streak.ID = primitive.NewObjectID()
+	}
+	streak.UpdatedAt = time.Now()
+
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"userId": streak.UserID},
+		streak,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
+
+func (r *mongoStreakRepository) MarkStale(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"userId": userID},
+		bson.M{
+			"$set": bson.M{
+				"stale":     true,
+				"updatedAt": time.Now(),
+			},
+		})
+	return err
+}
@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoTagRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoTagRepository(db *mongo.Database) interfaces.ITagRepository {
+	collection := db.Collection("tags")
+
+	// Create indexes
+	_, err := collection.Indexes().CreateMany(
+		context.Background(),
+		[]mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "userId", Value: 1}, {Key: "name", Value: 1},
+				},
+				Options: options.Index().SetUnique(true),
+			},
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoTagRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoTagRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) ([]*entity.Tag, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tags []*entity.Tag
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *mongoTagRepository) GetByName(ctx context.Context, userID primitive.ObjectID, name string) (*entity.Tag, error) {
+	var tag entity.Tag
+
+	err := r.collection.FindOne(ctx, bson.M{"userId": userID, "name": name}).Decode(&tag)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // No settings for this tag, not an error
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// Upsert stores the settings of a tag, keyed by user and name
+func (r *mongoTagRepository) Upsert(ctx context.Context, tag *entity.Tag) error {
+	if tag.ID.IsZero() {
+		tag.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"userId": tag.UserID, "name": tag.Name},
+		bson.M{
+			"$set": bson.M{
+				"color":     tag.Color,
+				"updatedAt": tag.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{
+				"_id":       tag.ID,
+				"createdAt": tag.CreatedAt,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+
+	return err
+}
+
+// Rename moves a tag's settings to a new name. Settings already stored under
+// the new name are kept.
+func (r *mongoTagRepository) Rename(ctx context.Context, userID primitive.ObjectID, from, to string) error {
+	existing, err := r.GetByName(ctx, userID, to)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return r.Delete(ctx, userID, []string{from})
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"userId": userID, "name": from},
+		bson.M{"$set": bson.M{
+			"name":      to,
+			"updatedAt": time.Now(),
+		}},
+	)
+
+	return err
+}
+
+func (r *mongoTagRepository) Delete(ctx context.Context, userID primitive.ObjectID, names []string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{
+		"userId": userID,
+		"name":   bson.M{"$in": names},
+	})
+	return err
+}
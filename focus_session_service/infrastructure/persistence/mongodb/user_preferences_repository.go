@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"focusspot/focussessionservice/domain/entity"
+	"focusspot/focussessionservice/domain/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoUserPreferencesRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoUserPreferencesRepository(db *mongo.Database) interfaces.IUserPreferencesRepository {
+	collection := db.Collection("user_preferences")
+
+	// One document per user
+	_, err := collection.Indexes().CreateOne(
+		context.Background(),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+
+	if err != nil {
+		// TODO: In production, handle this error properly
+		panic(err)
+	}
+
+	return &mongoUserPreferencesRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoUserPreferencesRepository) GetByUserID(ctx context.Context, userID primitive.ObjectID) (*entity.UserPreferences, error) {
+	var preferences entity.UserPreferences
+
+	err := r.collection.FindOne(ctx, bson.M{"userId": userID}).Decode(&preferences)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &preferences, nil
+}
+
+func (r *mongoUserPreferencesRepository) Save(ctx context.Context, preferences *entity.UserPreferences) error {
+	if preferences.ID.IsZero() {
+		preferences.ID = primitive.NewObjectID()
+	}
+	preferences.UpdatedAt = time.Now()
+
+	_, err := r.collection.ReplaceOne(
+		ctx,
+		bson.M{"userId": preferences.UserID},
+		preferences,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
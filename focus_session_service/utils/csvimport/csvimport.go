@@ -0,0 +1,387 @@
+package csvimport
+
+import (
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported formats
+const (
+	FormatToggl    = "toggl"
+	FormatClockify = "clockify"
+	FormatGeneric  = "generic"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown CSV format (use toggl, clockify or generic)")
+	ErrEmptyFile     = errors.New("CSV file has no header row")
+	ErrNoStartColumn = errors.New("a start column is required")
+	ErrNoEndColumn   = errors.New("an end or duration column is required")
+)
+
+// Date and time layouts tried when a mapping does not name one. Slash dates
+// are read month first, as Clockify and Toggl export them for US locales.
+var (
+	dateLayouts = []string{"2006-01-02", "01/02/2006", "2006/01/02", "02.01.2006"}
+	timeLayouts = []string{"15:04:05", "15:04", "03:04:05 PM", "3:04:05 PM", "03:04 PM", "3:04 PM"}
+	fullLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04"}
+)
+
+// Mapping names the columns of a CSV file. Column names are matched case
+// insensitively. Start and End hold a full timestamp, or only the date when
+// StartTime and EndTime name separate time columns. Duration is used when
+// the file has no end column.
+type Mapping struct {
+	ID          string
+	Title       string
+	Description string
+	Notes       string
+	Tags        string
+	Location    string
+	Start       string
+	StartTime   string
+	End         string
+	EndTime     string
+	Duration    string
+
+	// Layout is the Go time layout of the start and end values, with date
+	// and time joined by a space when they are in separate columns
+	Layout string
+
+	// TagSeparator splits the tags column; defaults to a comma
+	TagSeparator string
+}
+
+// Entry is a row read from a file. Err is set when the row could not be
+// read; the other rows are still returned.
+type Entry struct {
+	Row         int    // 1-based, header excluded
+	ID          string // stable across re-imports of the same row
+	Title       string
+	Description string
+	Notes       string
+	Location    string
+	Tags        []string
+	Start       time.Time
+	End         time.Time
+	Err         error
+}
+
+// Preset returns the column mapping of a time tracker's detailed report
+// export. Entries without a description are titled after their project.
+func Preset(format string) (Mapping, error) {
+	switch format {
+	case FormatToggl:
+		return Mapping{
+			Title:       "Description",
+			Description: "Project",
+			Tags:        "Tags",
+			Start:       "Start date",
+			StartTime:   "Start time",
+			End:         "End date",
+			EndTime:     "End time",
+			Duration:    "Duration",
+		}, nil
+	case FormatClockify:
+		return Mapping{
+			Title:       "Description",
+			Description: "Project",
+			Tags:        "Tags",
+			Start:       "Start Date",
+			StartTime:   "Start Time",
+			End:         "End Date",
+			EndTime:     "End Time",
+			Duration:    "Duration (h)",
+		}, nil
+	default:
+		return Mapping{}, ErrUnknownFormat
+	}
+}
+
+// Parse reads the rows of a CSV file using the given mapping. Times without
+// a zone are read in loc.
+func Parse(r io.Reader, mapping Mapping, loc *time.Location) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	p, err := newRowParser(mapping, columns, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				entries = append(entries, Entry{Row: row, Err: parseErr.Err})
+				continue
+			}
+			return nil, err
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		entries = append(entries, p.parse(row, record))
+	}
+
+	return entries, nil
+}
+
+// rowParser holds the column positions of a mapping; -1 marks a column the
+// file does not have
+type rowParser struct {
+	id, title, description, notes, tags, location int
+	start, startTime, end, endTime, duration      int
+
+	layouts      []string
+	tagSeparator string
+	loc          *time.Location
+}
+
+func newRowParser(mapping Mapping, columns map[string]int, loc *time.Location) (*rowParser, error) {
+	index := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("column %q not found", name)
+		}
+		return i, nil
+	}
+
+	// Optional columns may be missing from the file
+	optional := func(name string) int {
+		i, _ := index(name)
+		return i
+	}
+
+	p := &rowParser{
+		id:           optional(mapping.ID),
+		title:        optional(mapping.Title),
+		description:  optional(mapping.Description),
+		notes:        optional(mapping.Notes),
+		tags:         optional(mapping.Tags),
+		location:     optional(mapping.Location),
+		startTime:    optional(mapping.StartTime),
+		end:          optional(mapping.End),
+		endTime:      optional(mapping.EndTime),
+		duration:     optional(mapping.Duration),
+		tagSeparator: mapping.TagSeparator,
+		loc:          loc,
+	}
+
+	if mapping.Start == "" {
+		return nil, ErrNoStartColumn
+	}
+	var err error
+	if p.start, err = index(mapping.Start); err != nil {
+		return nil, err
+	}
+
+	if p.end < 0 && p.endTime < 0 && p.duration < 0 {
+		return nil, ErrNoEndColumn
+	}
+
+	if p.tagSeparator == "" {
+		p.tagSeparator = ","
+	}
+
+	if mapping.Layout != "" {
+		p.layouts = []string{mapping.Layout}
+	} else {
+		p.layouts = append(p.layouts, fullLayouts...)
+		for _, date := range dateLayouts {
+			p.layouts = append(p.layouts, date)
+			for _, clock := range timeLayouts {
+				p.layouts = append(p.layouts, date+" "+clock)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+func (p *rowParser) parse(row int, record []string) Entry {
+	entry := Entry{
+		Row:         row,
+		Title:       p.value(record, p.title),
+		Description: p.value(record, p.description),
+		Notes:       p.value(record, p.notes),
+		Location:    p.value(record, p.location),
+		Tags:        p.splitTags(p.value(record, p.tags)),
+	}
+
+	if entry.Title == "" {
+		entry.Title = entry.Description
+	}
+
+	var errs []string
+
+	startDate := p.value(record, p.start)
+	start, err := p.parseTime(startDate, p.value(record, p.startTime))
+	if err != nil {
+		errs = append(errs, "start: "+err.Error())
+	}
+	entry.Start = start
+
+	endDate := p.value(record, p.end)
+	endClock := p.value(record, p.endTime)
+	switch {
+	case endDate != "" || endClock != "":
+		// An end time alone is on the start date, or the next day when it
+		// would otherwise come first
+		sameDay := endDate == "" && p.startTime >= 0
+		if sameDay {
+			endDate = startDate
+		}
+		end, err := p.parseTime(endDate, endClock)
+		if err != nil {
+			errs = append(errs, "end: "+err.Error())
+			break
+		}
+		if sameDay && !start.IsZero() && !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		entry.End = end
+	case p.value(record, p.duration) != "":
+		duration, err := ParseDuration(p.value(record, p.duration))
+		if err != nil {
+			errs = append(errs, "duration: "+err.Error())
+			break
+		}
+		entry.End = start.Add(duration)
+	default:
+		errs = append(errs, "an end time or duration is required")
+	}
+
+	if len(errs) > 0 {
+		entry.Err = errors.New(strings.Join(errs, "; "))
+		return entry
+	}
+
+	entry.ID = p.value(record, p.id)
+	if entry.ID == "" {
+		entry.ID = rowID(entry)
+	}
+
+	return entry
+}
+
+func (p *rowParser) value(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (p *rowParser) parseTime(date, clock string) (time.Time, error) {
+	value := date
+	if clock != "" {
+		value = strings.TrimSpace(date + " " + clock)
+	}
+	if value == "" {
+		return time.Time{}, errors.New("value is required")
+	}
+
+	for _, layout := range p.layouts {
+		if t, err := time.ParseInLocation(layout, value, p.loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
+func (p *rowParser) splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, p.tagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ParseDuration reads h:mm:ss, h:mm, a Go duration such as 1h30m, or a
+// plain number of minutes
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		units := []time.Duration{time.Hour, time.Minute, time.Second}
+		var d time.Duration
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			d += time.Duration(n) * units[i]
+		}
+		return d, nil
+	}
+
+	if minutes, err := strconv.ParseFloat(value, 64); err == nil && minutes >= 0 {
+		return time.Duration(minutes * float64(time.Minute)), nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// rowID derives an ID from the row's content so that importing the same
+// file twice is detected
+func rowID(entry Entry) string {
+	sum := sha1.Sum([]byte(strings.Join([]string{
+		entry.Start.UTC().Format(time.RFC3339),
+		entry.End.UTC().Format(time.RFC3339),
+		entry.Title,
+		entry.Description,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
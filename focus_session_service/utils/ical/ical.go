@@ -0,0 +1,148 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineOctets is the longest content line allowed before folding (RFC 5545 3.1)
+const maxLineOctets = 75
+
+const dateTimeUTC = "20060102T150405Z"
+
+// Event statuses (RFC 5545 3.8.1.11)
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+type Geo struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Event is a VEVENT
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Geo          *Geo
+	Categories   []string
+	Status       string
+	Start        time.Time
+	End          time.Time
+	Created      time.Time
+	LastModified time.Time
+}
+
+// Calendar is a VCALENDAR with its events
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Write renders the calendar in iCalendar format
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", cal.ProdID)
+	lw.line("CALSCALE", "GREGORIAN")
+	lw.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		lw.line("X-WR-CALNAME", EscapeText(cal.Name))
+	}
+
+	stamp := time.Now().UTC().Format(dateTimeUTC)
+	for _, event := range cal.Events {
+		lw.line("BEGIN", "VEVENT")
+		lw.line("UID", event.UID)
+		lw.line("DTSTAMP", stamp)
+		lw.line("DTSTART", event.Start.UTC().Format(dateTimeUTC))
+		lw.line("DTEND", event.End.UTC().Format(dateTimeUTC))
+		lw.line("SUMMARY", EscapeText(event.Summary))
+		if event.Description != "" {
+			lw.line("DESCRIPTION", EscapeText(event.Description))
+		}
+		if event.Location != "" {
+			lw.line("LOCATION", EscapeText(event.Location))
+		}
+		if event.Geo != nil {
+			lw.line("GEO", fmt.Sprintf("%f;%f", event.Geo.Latitude, event.Geo.Longitude))
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = EscapeText(category)
+			}
+			lw.line("CATEGORIES", strings.Join(categories, ","))
+		}
+		if event.Status != "" {
+			lw.line("STATUS", event.Status)
+		}
+		if !event.Created.IsZero() {
+			lw.line("CREATED", event.Created.UTC().Format(dateTimeUTC))
+		}
+		if !event.LastModified.IsZero() {
+			lw.line("LAST-MODIFIED", event.LastModified.UTC().Format(dateTimeUTC))
+		}
+		lw.line("END", "VEVENT")
+	}
+
+	lw.line("END", "VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+// EscapeText escapes a TEXT property value (RFC 5545 3.3.11)
+func EscapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// lineWriter writes folded content lines and keeps the first error
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(name, value string) {
+	if lw.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+
+	// Fold long lines without splitting multi-byte characters
+	var b strings.Builder
+	lineLength := 0
+	for _, r := range content {
+		size := len(string(r))
+		if lineLength+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			lineLength = 1
+		}
+		b.WriteRune(r)
+		lineLength += size
+	}
+	b.WriteString("\r\n")
+
+	_, lw.err = lw.w.WriteString(b.String())
+}
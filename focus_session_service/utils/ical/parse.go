@@ -0,0 +1,333 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxParsedLineBytes bounds a single unfolded content line
+const maxParsedLineBytes = 1 << 20
+
+var (
+	ErrNotCalendar    = errors.New("file is not an iCalendar (VCALENDAR) file")
+	ErrRecurringEvent = errors.New("recurring events are not supported")
+
+	durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+)
+
+// ParsedEvent is a VEVENT read from a file. Err is set when the event could
+// not be read; the other events are still returned. Recurring events, and
+// overrides of their instances, are not expanded: their Err is
+// ErrRecurringEvent.
+type ParsedEvent struct {
+	Event
+	AllDay bool
+	Err    error
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs of an iCalendar stream. Components nested in an
+// event, such as VALARM, are skipped.
+func Parse(r io.Reader) ([]ParsedEvent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ParsedEvent
+	var current []property
+	inCalendar, inEvent := false, false
+	nested := 0
+
+	for _, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			if inEvent {
+				current = append(current, property{name: "X-INVALID", value: err.Error()})
+			}
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			inCalendar = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && inCalendar:
+			inEvent = true
+			current = nil
+		case prop.name == "BEGIN" && inEvent:
+			nested++
+		case prop.name == "END" && inEvent && nested > 0:
+			nested--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && inEvent:
+			events = append(events, buildEvent(current))
+			inEvent = false
+		case inEvent && nested == 0:
+			current = append(current, prop)
+		}
+	}
+
+	if !inCalendar {
+		return nil, ErrNotCalendar
+	}
+
+	return events, nil
+}
+
+// UnescapeText reverses EscapeText
+func UnescapeText(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func buildEvent(props []property) ParsedEvent {
+	var event ParsedEvent
+	var duration *time.Duration
+	var errs []string
+	recurring := false
+
+	for _, prop := range props {
+		var err error
+		switch prop.name {
+		case "UID":
+			event.UID = strings.TrimSpace(prop.value)
+		case "SUMMARY":
+			event.Summary = UnescapeText(prop.value)
+		case "DESCRIPTION":
+			event.Description = UnescapeText(prop.value)
+		case "LOCATION":
+			event.Location = UnescapeText(prop.value)
+		case "STATUS":
+			event.Status = strings.ToUpper(prop.value)
+		case "CATEGORIES":
+			event.Categories = append(event.Categories, splitList(prop.value)...)
+		case "GEO":
+			event.Geo, err = parseGeo(prop.value)
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseDateTime(prop)
+		case "DTEND":
+			event.End, _, err = parseDateTime(prop)
+		case "DURATION":
+			var d time.Duration
+			d, err = parseDuration(prop.value)
+			duration = &d
+		case "RRULE", "RDATE", "EXDATE", "RECURRENCE-ID":
+			recurring = true
+		case "X-INVALID":
+			err = errors.New(prop.value)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", prop.name, err))
+		}
+	}
+
+	// Only the first instance would be imported, and overrides would be
+	// taken for duplicates of it
+	if recurring {
+		event.Err = ErrRecurringEvent
+		return event
+	}
+
+	if event.Start.IsZero() && len(errs) == 0 {
+		errs = append(errs, "DTSTART is required")
+	}
+
+	if event.End.IsZero() && !event.Start.IsZero() {
+		switch {
+		case duration != nil:
+			event.End = event.Start.Add(*duration)
+		case event.AllDay:
+			event.End = event.Start.AddDate(0, 0, 1)
+		default:
+			event.End = event.Start
+		}
+	}
+
+	if len(errs) > 0 {
+		event.Err = errors.New(strings.Join(errs, "; "))
+	}
+
+	return event
+}
+
+// unfold joins folded content lines (RFC 5545 3.1)
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxParsedLineBytes)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+func parseProperty(line string) (property, error) {
+	// The value starts at the first colon outside a quoted parameter
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("malformed line %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	prop := property{
+		name:   strings.ToUpper(head[0]),
+		params: make(map[string]string),
+		value:  line[colon+1:],
+	}
+
+	for _, param := range head[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+func parseDateTime(prop property) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown timezone %q", tzid)
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * unit
+	}
+
+	if match[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func parseGeo(value string) (*Geo, error) {
+	lat, lng, ok := strings.Cut(value, ";")
+	if !ok {
+		return nil, fmt.Errorf("invalid geo %q", value)
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return nil, err
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Geo{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// splitList splits a comma separated TEXT list, keeping escaped commas
+func splitList(value string) []string {
+	var items []string
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			items = append(items, UnescapeText(b.String()))
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	items = append(items, UnescapeText(b.String()))
+
+	result := items[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,537 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods bounds how many periods are walked while expanding a rule
+const maxPeriods = 100000
+
+var (
+	ErrInvalidRule     = errors.New("invalid recurrence rule")
+	ErrUnsupportedPart = errors.New("unsupported recurrence rule part")
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry, e.g. MO, 1MO (first Monday) or -1FR (last
+// Friday) of the month, or of the year for yearly rules without BYMONTH
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int // 0 matches every such weekday in the period
+}
+
+// Rule is a parsed RFC 5545 RRULE. BYSETPOS, BYWEEKNO, BYYEARDAY and
+// BYSECOND are not supported.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time // UTC, or a wall-clock time when FloatingUntil
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	ByHour     []int
+	ByMinute   []int
+	WeekStart  time.Weekday
+
+	// FloatingUntil is set for an UNTIL without "Z", a date or a local date
+	// and time. It is read in dtstart's location when the rule is expanded.
+	FloatingUntil bool
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR".
+// A leading "RRULE:" is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "RRULE:"), "rrule:")
+	if value == "" {
+		return nil, ErrInvalidRule
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			switch rule.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				return nil, fmt.Errorf("%w: FREQ=%s", ErrUnsupportedPart, val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+			if err == nil && rule.Interval < 1 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+			if err == nil && rule.Count < 1 {
+				err = errors.New("must be positive")
+			}
+		case "UNTIL":
+			var until time.Time
+			until, rule.FloatingUntil, err = parseUntil(val)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(val, -31, 31, false)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(val, 1, 12, false)
+			for _, m := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(m))
+			}
+		case "BYHOUR":
+			rule.ByHour, err = parseInts(val, 0, 23, true)
+		case "BYMINUTE":
+			rule.ByMinute, err = parseInts(val, 0, 59, true)
+		case "WKST":
+			day, exists := weekdayCodes[strings.ToUpper(val)]
+			if !exists {
+				err = errors.New("unknown weekday")
+			}
+			rule.WeekStart = day
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedPart, key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRule, key, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+
+	// Beyond the fifth, ordinals only fit in a year
+	for _, d := range rule.ByDay {
+		if (d.N < -5 || d.N > 5) && (rule.Freq != Yearly || len(rule.ByMonth) > 0) {
+			return nil, fmt.Errorf("%w: BYDAY: ordinal %d is out of range", ErrInvalidRule, d.N)
+		}
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL cannot both be set", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// String formats the rule back into RRULE value syntax
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		if r.FloatingUntil {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]int, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = int(m)
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayCode(d.Weekday)
+			if d.N != 0 {
+				days[i] = strconv.Itoa(d.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByHour) > 0 {
+		parts = append(parts, "BYHOUR="+joinInts(r.ByHour))
+	}
+	if len(r.ByMinute) > 0 {
+		parts = append(parts, "BYMINUTE="+joinInts(r.ByMinute))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCode(r.WeekStart))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of the rule starting at dtstart that fall
+// in [from, to). Occurrences are built in dtstart's location, so wall-clock
+// times are kept across DST changes.
+func (r *Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	r.iterate(dtstart, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// CountBefore returns how many occurrences happen before t
+func (r *Rule) CountBefore(dtstart, t time.Time) int {
+	count := 0
+	r.iterate(dtstart, func(occurrence time.Time) bool {
+		if !occurrence.Before(t) {
+			return false
+		}
+		count++
+		return true
+	})
+	return count
+}
+
+// Includes reports whether t is an occurrence of the rule
+func (r *Rule) Includes(dtstart, t time.Time) bool {
+	found := false
+	r.iterate(dtstart, func(occurrence time.Time) bool {
+		if occurrence.Equal(t) {
+			found = true
+		}
+		return occurrence.Before(t)
+	})
+	return found
+}
+
+// iterate calls fn for each occurrence in order until fn returns false or
+// the rule ends
+func (r *Rule) iterate(dtstart time.Time, fn func(time.Time) bool) {
+	emitted := 0
+	until := r.until(dtstart.Location())
+
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.candidates(dtstart, period)
+
+		for _, t := range candidates {
+			if t.Before(dtstart) {
+				continue
+			}
+			if until != nil && t.After(*until) {
+				return
+			}
+			if !fn(t) {
+				return
+			}
+
+			emitted++
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// until returns the end of the rule. A floating UNTIL is the same wall-clock
+// time in loc.
+func (r *Rule) until(loc *time.Location) *time.Time {
+	if r.Until == nil || !r.FloatingUntil {
+		return r.Until
+	}
+
+	u := r.Until
+	until := time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), 0, loc)
+	return &until
+}
+
+// candidates returns the sorted occurrences within the n-th period after dtstart
+func (r *Rule) candidates(dtstart time.Time, n int) []time.Time {
+	loc := dtstart.Location()
+	step := n * r.Interval
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day()+step, 0, 0, 0, 0, loc)
+		if r.matchesMonth(day.Month()) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+7*step, 0, 0, 0, 0, loc)
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if !r.matchesMonth(day.Month()) {
+				continue
+			}
+			if len(r.ByDay) > 0 {
+				if r.matchesWeekday(day) {
+					days = append(days, day)
+				}
+			} else if day.Weekday() == dtstart.Weekday() {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		month := time.Date(dtstart.Year(), dtstart.Month()+time.Month(step), 1, 0, 0, 0, 0, loc)
+		if r.matchesMonth(month.Month()) {
+			days = r.daysInMonth(dtstart, month)
+		}
+	case Yearly:
+		year := dtstart.Year() + step
+		switch {
+		case len(r.ByMonth) > 0:
+			for _, m := range r.ByMonth {
+				days = append(days, r.daysInMonth(dtstart, time.Date(year, m, 1, 0, 0, 0, 0, loc))...)
+			}
+		case len(r.ByMonthDay) > 0 || len(r.ByDay) > 0:
+			days = r.daysInYear(year, loc)
+		default:
+			days = r.daysInMonth(dtstart, time.Date(year, dtstart.Month(), 1, 0, 0, 0, 0, loc))
+		}
+	}
+
+	hours := r.ByHour
+	if len(hours) == 0 {
+		hours = []int{dtstart.Hour()}
+	}
+	minutes := r.ByMinute
+	if len(minutes) == 0 {
+		minutes = []int{dtstart.Minute()}
+	}
+
+	occurrences := make([]time.Time, 0, len(days)*len(hours)*len(minutes))
+	for _, day := range days {
+		for _, hour := range hours {
+			for _, minute := range minutes {
+				occurrences = append(occurrences, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, dtstart.Second(), 0, loc))
+			}
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Before(occurrences[j])
+	})
+
+	return occurrences
+}
+
+// daysInMonth returns the days of the month selected by BYMONTHDAY and
+// BYDAY, or dtstart's day of month when neither is set
+func (r *Rule) daysInMonth(dtstart, month time.Time) []time.Time {
+	lastDay := month.AddDate(0, 1, -1).Day()
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if dtstart.Day() > lastDay {
+			return nil
+		}
+		return []time.Time{month.AddDate(0, 0, dtstart.Day()-1)}
+	}
+
+	var days []time.Time
+	for d := 1; d <= lastDay; d++ {
+		day := month.AddDate(0, 0, d-1)
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(day) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesNthWeekday(day.Weekday(), d, lastDay) {
+			continue
+		}
+		days = append(days, day)
+	}
+
+	return days
+}
+
+// daysInYear returns the days of the year selected by BYMONTHDAY and BYDAY,
+// for yearly rules without BYMONTH. BYDAY ordinals count within the year.
+func (r *Rule) daysInYear(year int, loc *time.Location) []time.Time {
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	lastDay := time.Date(year, time.December, 31, 0, 0, 0, 0, loc).YearDay()
+
+	var days []time.Time
+	for d := 1; d <= lastDay; d++ {
+		day := first.AddDate(0, 0, d-1)
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(day) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesNthWeekday(day.Weekday(), d, lastDay) {
+			continue
+		}
+		days = append(days, day)
+	}
+
+	return days
+}
+
+func (r *Rule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, d := range r.ByMonthDay {
+		if d == day.Day() || (d < 0 && lastDay+d+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday ignores BYDAY ordinals, which only apply within a month or year
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNthWeekday reports whether the day-th day of a month or year, of
+// lastDay days, is selected by BYDAY
+func (r *Rule) matchesNthWeekday(weekday time.Weekday, day, lastDay int) bool {
+	for _, d := range r.ByDay {
+		if d.Weekday != weekday {
+			continue
+		}
+		if d.N == 0 {
+			return true
+		}
+
+		// 1 is the first such weekday of the period, -1 the last
+		fromStart := (day-1)/7 + 1
+		fromEnd := -((lastDay-day)/7 + 1)
+		if d.N == fromStart || d.N == fromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUntil reads UNTIL and reports whether it is floating. Only the "Z"
+// form is UTC; the others are wall-clock times, kept in UTC until dtstart's
+// location is known.
+func parseUntil(value string) (time.Time, bool, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, layout != "20060102T150405Z", nil
+		}
+	}
+	return time.Time{}, false, errors.New("invalid date")
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		day, exists := weekdayCodes[item[len(item)-2:]]
+		if !exists {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		n := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday %q", item)
+			}
+		}
+
+		days = append(days, WeekdayNum{Weekday: day, N: n})
+	}
+	return days, nil
+}
+
+func parseInts(value string, min, max int, allowZero bool) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || n < min || n > max || (n == 0 && !allowZero) {
+			return nil, fmt.Errorf("invalid value %q", item)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func joinInts(values []int) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = strconv.Itoa(v)
+	}
+	return strings.Join(items, ",")
+}
+
+func weekdayCode(day time.Weekday) string {
+	for code, d := range weekdayCodes {
+		if d == day {
+			return code
+		}
+	}
+	return ""
+}
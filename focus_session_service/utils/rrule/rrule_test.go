@@ -0,0 +1,236 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata" // CI images may have no zoneinfo
+)
+
+type expansionCase struct {
+	name     string
+	rule     string
+	timezone string
+	dtstart  string // local date and time in timezone
+	to       string // end of the window, local
+	want     []string
+}
+
+// checkExpansions expands each rule from dtstart up to the window's end and
+// compares the occurrences as local date-times
+func checkExpansions(t *testing.T, cases []expansionCase) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tc.timezone)
+			if err != nil {
+				t.Fatalf("LoadLocation(%q): %v", tc.timezone, err)
+			}
+
+			rule, err := Parse(tc.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tc.rule, err)
+			}
+
+			dtstart := localTime(t, tc.dtstart, loc)
+			to := localTime(t, tc.to, loc)
+
+			var got []string
+			for _, occurrence := range rule.Between(dtstart, dtstart, to) {
+				if occurrence.Location() != loc {
+					t.Errorf("occurrence %v is not in %s", occurrence, loc)
+				}
+				got = append(got, occurrence.Format("2006-01-02 15:04"))
+			}
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(tc.want), tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("occurrence %d = %s, want %s", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func localTime(t *testing.T, value string, loc *time.Location) time.Time {
+	t.Helper()
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatalf("ParseInLocation(%q): %v", value, err)
+	}
+	return parsed
+}
+
+func TestWeeklyAcrossDST(t *testing.T) {
+	checkExpansions(t, []expansionCase{
+		{
+			name:     "spring forward",
+			rule:     "FREQ=WEEKLY;BYDAY=MO;COUNT=3",
+			timezone: "America/New_York",
+			dtstart:  "2026-03-02 09:00",
+			to:       "2026-04-01 00:00",
+			want:     []string{"2026-03-02 09:00", "2026-03-09 09:00", "2026-03-16 09:00"},
+		},
+		{
+			name:     "fall back",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,SU",
+			timezone: "Europe/Berlin",
+			dtstart:  "2026-10-19 08:00",
+			to:       "2026-10-27 00:00",
+			want:     []string{"2026-10-19 08:00", "2026-10-25 08:00", "2026-10-26 08:00"},
+		},
+		{
+			name:     "every other week",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH",
+			timezone: "Australia/Lord_Howe",
+			dtstart:  "2026-03-24 07:30",
+			to:       "2026-04-17 00:00",
+			want:     []string{"2026-03-24 07:30", "2026-03-26 07:30", "2026-04-07 07:30", "2026-04-09 07:30"},
+		},
+	})
+}
+
+func TestYearly(t *testing.T) {
+	checkExpansions(t, []expansionCase{
+		{
+			name:     "dtstart's day",
+			rule:     "FREQ=YEARLY;COUNT=3",
+			timezone: "UTC",
+			dtstart:  "2026-02-10 09:00",
+			to:       "2030-01-01 00:00",
+			want:     []string{"2026-02-10 09:00", "2027-02-10 09:00", "2028-02-10 09:00"},
+		},
+		{
+			name:     "every Monday of the year",
+			rule:     "FREQ=YEARLY;BYDAY=MO",
+			timezone: "UTC",
+			dtstart:  "2026-01-05 09:00",
+			to:       "2026-03-03 00:00",
+			want: []string{
+				"2026-01-05 09:00", "2026-01-12 09:00", "2026-01-19 09:00", "2026-01-26 09:00",
+				"2026-02-02 09:00", "2026-02-09 09:00", "2026-02-16 09:00", "2026-02-23 09:00",
+				"2026-03-02 09:00",
+			},
+		},
+		{
+			name:     "20th Monday of the year",
+			rule:     "FREQ=YEARLY;BYDAY=20MO;COUNT=2",
+			timezone: "UTC",
+			dtstart:  "2026-01-01 09:00",
+			to:       "2030-01-01 00:00",
+			want:     []string{"2026-05-18 09:00", "2027-05-17 09:00"},
+		},
+		{
+			name:     "last Friday of the year",
+			rule:     "FREQ=YEARLY;BYDAY=-1FR;COUNT=2",
+			timezone: "UTC",
+			dtstart:  "2026-01-01 09:00",
+			to:       "2030-01-01 00:00",
+			want:     []string{"2026-12-25 09:00", "2027-12-31 09:00"},
+		},
+		{
+			name:     "last Friday of March",
+			rule:     "FREQ=YEARLY;BYMONTH=3;BYDAY=-1FR;COUNT=2",
+			timezone: "UTC",
+			dtstart:  "2026-01-01 09:00",
+			to:       "2030-01-01 00:00",
+			want:     []string{"2026-03-27 09:00", "2027-03-26 09:00"},
+		},
+		{
+			name:     "first of every month",
+			rule:     "FREQ=YEARLY;BYMONTHDAY=1;COUNT=3",
+			timezone: "UTC",
+			dtstart:  "2026-01-01 09:00",
+			to:       "2030-01-01 00:00",
+			want:     []string{"2026-01-01 09:00", "2026-02-01 09:00", "2026-03-01 09:00"},
+		},
+	})
+}
+
+func TestMonthly(t *testing.T) {
+	checkExpansions(t, []expansionCase{
+		{
+			name:     "second Tuesday",
+			rule:     "FREQ=MONTHLY;BYDAY=2TU;COUNT=3",
+			timezone: "UTC",
+			dtstart:  "2026-01-01 18:00",
+			to:       "2030-01-01 00:00",
+			want:     []string{"2026-01-13 18:00", "2026-02-10 18:00", "2026-03-10 18:00"},
+		},
+		{
+			name:     "last day",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			timezone: "UTC",
+			dtstart:  "2026-01-01 18:00",
+			to:       "2030-01-01 00:00",
+			want:     []string{"2026-01-31 18:00", "2026-02-28 18:00", "2026-03-31 18:00"},
+		},
+	})
+}
+
+func TestParseRejectsOrdinalsOutsideTheYear(t *testing.T) {
+	for _, value := range []string{
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=YEARLY;BYMONTH=3;BYDAY=-6FR",
+		"FREQ=YEARLY;BYDAY=54MO",
+	} {
+		if _, err := Parse(value); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidRule", value, err)
+		}
+	}
+}
+
+func TestUntil(t *testing.T) {
+	checkExpansions(t, []expansionCase{
+		{
+			name:     "date east of UTC",
+			rule:     "FREQ=DAILY;UNTIL=20261231",
+			timezone: "Asia/Ho_Chi_Minh",
+			dtstart:  "2026-12-30 06:00",
+			to:       "2027-01-10 00:00",
+			want:     []string{"2026-12-30 06:00", "2026-12-31 06:00"},
+		},
+		{
+			name:     "date west of UTC",
+			rule:     "FREQ=DAILY;UNTIL=20261231",
+			timezone: "America/New_York",
+			dtstart:  "2026-12-30 20:00",
+			to:       "2027-01-10 00:00",
+			want:     []string{"2026-12-30 20:00", "2026-12-31 20:00"},
+		},
+		{
+			name:     "floating date-time",
+			rule:     "FREQ=DAILY;UNTIL=20261231T200000",
+			timezone: "America/New_York",
+			dtstart:  "2026-12-30 20:00",
+			to:       "2027-01-10 00:00",
+			want:     []string{"2026-12-30 20:00", "2026-12-31 20:00"},
+		},
+		{
+			name:     "UTC date-time",
+			rule:     "FREQ=DAILY;UNTIL=20261230T000000Z",
+			timezone: "Asia/Ho_Chi_Minh",
+			dtstart:  "2026-12-30 06:00",
+			to:       "2027-01-10 00:00",
+			want:     []string{"2026-12-30 06:00"},
+		},
+	})
+}
+
+func TestStringKeepsFloatingUntil(t *testing.T) {
+	for value, want := range map[string]string{
+		"FREQ=DAILY;UNTIL=20261231":         "FREQ=DAILY;UNTIL=20261231T235959",
+		"FREQ=DAILY;UNTIL=20261231T200000":  "FREQ=DAILY;UNTIL=20261231T200000",
+		"FREQ=DAILY;UNTIL=20261231T200000Z": "FREQ=DAILY;UNTIL=20261231T200000Z",
+	} {
+		rule, err := Parse(value)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", value, err)
+		}
+		if got := rule.String(); got != want {
+			t.Errorf("Parse(%q).String() = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package snippet
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Highlight markers put around matched words
+const (
+	MarkStart = "<mark>"
+	MarkEnd   = "</mark>"
+)
+
+// English suffixes dropped from search terms, so that a search for
+// "reflections" highlights "reflection" the way the text index matches it
+var suffixes = []string{"ing", "ed", "es", "s"}
+
+// Terms returns the words of a MongoDB $text search, without negated words
+// and in lower case. Quoted phrases are split into their words.
+func Terms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+
+		for _, word := range strings.FieldsFunc(field, isSeparator) {
+			terms = append(terms, stem(strings.ToLower(word)))
+		}
+	}
+	return terms
+}
+
+// Make returns an excerpt of text around its first match, with roughly
+// radius runes of context on each side. The excerpt is HTML escaped and every
+// matched word is wrapped in MarkStart and MarkEnd. It returns false when no
+// term matches.
+func Make(text string, terms []string, radius int) (string, bool) {
+	words := splitWords(text)
+
+	first := -1
+	for i, w := range words {
+		if matches(w.value, terms) {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	runes := []rune(text)
+	from := words[first].start - radius
+	to := words[first].end + radius
+	if from < 0 {
+		from = 0
+	}
+	if to > len(runes) {
+		to = len(runes)
+	}
+
+	// Do not cut words in half at the edges
+	for from > 0 && !isSeparator(runes[from-1]) {
+		from--
+	}
+	for to < len(runes) && !isSeparator(runes[to]) {
+		to++
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+
+	position := from
+	for _, w := range words {
+		if w.start < from || w.end > to || !matches(w.value, terms) {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[position:w.start])))
+		b.WriteString(MarkStart)
+		b.WriteString(html.EscapeString(string(runes[w.start:w.end])))
+		b.WriteString(MarkEnd)
+		position = w.end
+	}
+	b.WriteString(html.EscapeString(string(runes[position:to])))
+
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return strings.Join(strings.Fields(b.String()), " "), true
+}
+
+type word struct {
+	value      string // lower case
+	start, end int    // rune offsets
+}
+
+func splitWords(text string) []word {
+	var words []word
+	start := -1
+	runes := []rune(text)
+	for i, r := range runes {
+		if isSeparator(r) {
+			if start >= 0 {
+				words = append(words, word{strings.ToLower(string(runes[start:i])), start, i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{strings.ToLower(string(runes[start:])), start, len(runes)})
+	}
+	return words
+}
+
+func matches(value string, terms []string) bool {
+	for _, term := range terms {
+		if term != "" && strings.HasPrefix(value, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func stem(term string) string {
+	for _, suffix := range suffixes {
+		if len(term) > len(suffix)+2 && strings.HasSuffix(term, suffix) {
+			return strings.TrimSuffix(term, suffix)
+		}
+	}
+	return term
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}